@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/Fedosin/kube-dethrottler/internal/controller"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+)
+
+// exitCode is returned by a command to exit with a specific status.
+type exitCode int
+
+func (e exitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// exitWouldTaint is the status of check when at least one node would be
+// put under pressure.
+const exitWouldTaint exitCode = 3
+
+func runCheck(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("kube-dethrottler check", flag.ContinueOnError)
+	client := addClientFlags(flags)
+	nodeName := flags.String("node", "", "Check only this node.")
+	output := flags.String("output", outputTable, "Output format: table, json or yaml.")
+	flags.StringVar(output, "o", outputTable, "Shorthand for --output.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, kubeClient, err := client.load()
+	if err != nil {
+		return err
+	}
+	nodes, err := kubeClient.ListNodes(ctx, cfg.NodeFilter)
+	if err != nil {
+		return err
+	}
+	if *nodeName != "" {
+		nodes, err = selectNode(nodes, *nodeName)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctrl := controller.NewController(cfg, kubeClient, buildPSISource(cfg, kubeClient), commandLogger())
+	evaluations := ctrl.CheckNodes(ctx, nodes)
+	err = writeOutput(stdout, *output, evaluations, func(w io.Writer) {
+		fmt.Fprintln(w, "NODE\tCHECK\tVALUE\tTHRESHOLD\tVERDICT")
+		for _, e := range evaluations {
+			for _, row := range verdicts(e) {
+				fmt.Fprintln(w, row)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range evaluations {
+		if wouldTaint(e) {
+			return exitWouldTaint
+		}
+	}
+	return nil
+}
+
+// selectNode returns the node named name, which must match the node filter.
+func selectNode(nodes []kubernetes.NodeInfo, name string) ([]kubernetes.NodeInfo, error) {
+	for _, node := range nodes {
+		if node.Name == name {
+			return []kubernetes.NodeInfo{node}, nil
+		}
+	}
+	return nil, fmt.Errorf("node %s not found or not selected by nodeFilter", name)
+}
+
+// wouldTaint reports whether the evaluated node would be put under pressure.
+func wouldTaint(e controller.Evaluation) bool {
+	return e.Skipped == "" && e.Error == "" && len(e.Fired) > 0
+}
+
+// verdicts formats the table rows of an evaluation: one per check, and one
+// summarising the node.
+func verdicts(e controller.Evaluation) []string {
+	var rows []string
+	for _, check := range e.Checks {
+		verdict := "ok"
+		if check.Fired {
+			verdict = "exceeded"
+		}
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%.2f\t%.2f\t%s", e.Node, check.Name, check.Value, check.Threshold, verdict))
+	}
+
+	var summary string
+	switch {
+	case e.Error != "":
+		summary = "error: " + e.Error
+	case e.Skipped != "":
+		summary = "skipped: " + e.Skipped
+	case wouldTaint(e) && e.Tainted:
+		summary = "under pressure, already applied: " + e.Action.Actions
+	case wouldTaint(e):
+		summary = "would apply " + e.Action.Actions
+	case e.Tainted:
+		summary = "below thresholds, would release after cooldown: " + e.Action.Actions
+	default:
+		summary = "below thresholds"
+	}
+	return append(rows, fmt.Sprintf("%s\t-\t\t\t%s", e.Node, summary))
+}
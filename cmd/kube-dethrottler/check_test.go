@@ -0,0 +1,62 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Fedosin/kube-dethrottler/internal/controller"
+)
+
+func TestVerdicts(t *testing.T) {
+	action := controller.Action{Actions: "taint kube-dethrottler/high-load:NoSchedule", Result: controller.ResultNone}
+	checks := []controller.Check{
+		{Name: "cpu.some.avg10", Value: 50, Threshold: 25, Fired: true},
+		{Name: "cpu.some.avg60", Value: 20, Threshold: 40},
+	}
+
+	tests := []struct {
+		name       string
+		evaluation controller.Evaluation
+		want       []string
+		wouldTaint bool
+	}{
+		{
+			name:       "exceeded",
+			evaluation: controller.Evaluation{Node: "node-1", Checks: checks, Fired: []string{"cpu.some.avg10"}, Action: action},
+			want: []string{
+				"node-1\tcpu.some.avg10\t50.00\t25.00\texceeded",
+				"node-1\tcpu.some.avg60\t20.00\t40.00\tok",
+				"node-1\t-\t\t\twould apply taint kube-dethrottler/high-load:NoSchedule",
+			},
+			wouldTaint: true,
+		},
+		{
+			name:       "tainted and recovered",
+			evaluation: controller.Evaluation{Node: "node-1", Checks: checks[1:], Tainted: true, Action: action},
+			want: []string{
+				"node-1\tcpu.some.avg60\t20.00\t40.00\tok",
+				"node-1\t-\t\t\tbelow thresholds, would release after cooldown: taint kube-dethrottler/high-load:NoSchedule",
+			},
+		},
+		{
+			name:       "skipped",
+			evaluation: controller.Evaluation{Node: "node-1", Fired: []string{"missingData"}, Skipped: "unknown"},
+			want:       []string{"node-1\t-\t\t\tskipped: unknown"},
+		},
+		{
+			name:       "error",
+			evaluation: controller.Evaluation{Node: "node-1", Error: "summary unavailable"},
+			want:       []string{"node-1\t-\t\t\terror: summary unavailable"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verdicts(tt.evaluation); !slices.Equal(got, tt.want) {
+				t.Errorf("verdicts() = %q, want %q", got, tt.want)
+			}
+			if got := wouldTaint(tt.evaluation); got != tt.wouldTaint {
+				t.Errorf("wouldTaint() = %v, want %v", got, tt.wouldTaint)
+			}
+		})
+	}
+}
@@ -2,9 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	// Embed the time zone database for schedules in minimal images.
+	_ "time/tzdata"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/leaderelection"
@@ -13,26 +23,110 @@ import (
 	"github.com/Fedosin/kube-dethrottler/internal/config"
 	"github.com/Fedosin/kube-dethrottler/internal/controller"
 	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
 	"github.com/Fedosin/kube-dethrottler/internal/psi"
 )
 
+// command is a kube-dethrottler subcommand. run parses args and writes
+// its output to stdout.
+type command struct {
+	run     func(ctx context.Context, args []string, stdout io.Writer) error
+	name    string
+	summary string
+}
+
+var commands = []command{
+	{name: "status", summary: "List the nodes under pressure, why and their current PSI", run: runStatus},
+	{name: "check", summary: "Evaluate nodes once without acting; exits 3 if any would be put under pressure", run: runCheck},
+	{name: "pause", summary: "Suspend all changes to nodes by the running controller", run: runPause},
+	{name: "resume", summary: "Resume changes to nodes by the running controller", run: runResume},
+	{name: "validate-config", summary: "Validate configuration files strictly, or print their JSON Schema", run: runValidateConfig},
+	{name: "record", summary: "Record the PSI of all nodes to a trace for simulate", run: runRecord},
+	{name: "simulate", summary: "Replay a trace through a configuration and report taints and flaps", run: runSimulate},
+	{name: "untaint-all", summary: "Remove the controller's taint from all nodes", run: runUntaintAll},
+}
+
 func main() {
-	logger := log.New(os.Stdout, "kube-dethrottler: ", log.LstdFlags|log.Lshortfile)
+	name, args := "run", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	switch name {
+	case "run":
+		runController(args)
+		return
+	case "help":
+		usage(os.Stdout)
+		return
+	}
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err := cmd.run(ctx, args, os.Stdout)
+		stop()
+		var code exitCode
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return
+		case errors.As(err, &code):
+			os.Exit(int(code))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kube-dethrottler %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "kube-dethrottler: unknown command %q\n\n", name)
+	usage(os.Stderr)
+	os.Exit(2)
+}
 
-	configFile := flag.String("config", "/etc/kube-dethrottler/config.yaml", "Path to the configuration file.")
-	flag.Parse()
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: kube-dethrottler [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintf(w, "  %-16s %s\n", "run", "Run the controller (default)")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'kube-dethrottler <command> -h' for the flags of a command.")
+}
+
+// runController runs the controller until it receives a termination signal.
+func runController(args []string) {
+	flags := flag.NewFlagSet("kube-dethrottler run", flag.ExitOnError)
+	configFile := flags.String("config", defaultConfigFile, "Path to the configuration file.")
+	logLevel := flags.String("log-level", "", "Log level: debug, info, warn or error. Overrides logging.level.")
+	logFormat := flags.String("log-format", "", "Log format: text or json. Overrides logging.format.")
+	_ = flags.Parse(args)
+
+	// Log with the flags until the configuration is loaded.
+	logger, err := logging.New(os.Stdout, *logLevel, *logFormat)
+	if err != nil {
+		fatal(slog.Default(), "Invalid logging flags", logging.Err(err))
+	}
 
 	cfg, err := config.LoadConfig(*configFile)
 	if err != nil {
-		logger.Fatalf("Failed to load configuration from %s: %v", *configFile, err)
+		fatal(logger, "Failed to load configuration", slog.String("path", *configFile), logging.Err(err))
+	}
+
+	logger, err = logging.New(os.Stdout, flagOr(*logLevel, cfg.Logging.Level), flagOr(*logFormat, cfg.Logging.Format))
+	if err != nil {
+		fatal(logger, "Invalid logging configuration", logging.Err(err))
 	}
+	slog.SetDefault(logger)
 
-	kubeClient, err := kubernetes.NewClient(cfg.KubeconfigPath)
+	kubeClient, err := kubernetes.NewClient(cfg.KubeconfigPath, logger)
 	if err != nil {
-		logger.Fatalf("Failed to create Kubernetes client: %v", err)
+		fatal(logger, "Failed to create Kubernetes client", logging.Err(err))
 	}
 
-	psiFetcher := psi.NewFetcher(kubeClient.Clientset())
+	psiFetcher := buildPSISource(cfg, kubeClient)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -41,19 +135,74 @@ func main() {
 
 	ctrl := controller.NewController(cfg, kubeClient, psiFetcher, logger)
 
+	if cfg.DebugServer.Enabled {
+		go serveDebug(ctx, cfg.DebugServer.Address, ctrl.DebugHandler(), logger)
+	}
+
 	if cfg.LeaderElection.Enabled {
 		runWithLeaderElection(ctx, cancel, cfg, kubeClient, ctrl, logger)
 	} else {
 		ctrl.Run(ctx)
 	}
 
-	logger.Println("kube-dethrottler has shut down.")
+	logger.Info("kube-dethrottler has shut down")
+}
+
+// serveDebug serves the controller's debug endpoints until ctx is cancelled.
+func serveDebug(ctx context.Context, addr string, handler http.Handler, logger *slog.Logger) {
+	server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+	logger.Info("Serving debug endpoints", slog.String("address", addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Debug server failed", logging.Err(err))
+	}
+}
+
+// flagOr returns the flag value if it was set, or the configured value.
+func flagOr(flagValue, configValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return configValue
+}
+
+// fatal logs an error and exits.
+func fatal(logger *slog.Logger, msg string, args ...any) {
+	logger.Error(msg, args...)
+	os.Exit(1)
+}
+
+// buildPSISource assembles the PSI sources configured in cfg.Sources.Order.
+func buildPSISource(cfg *config.Config, kubeClient *kubernetes.Client) psi.Source {
+	var sources []psi.Source
+	var lastKnownMaxAge time.Duration
+	for _, name := range cfg.Sources.Order {
+		switch name {
+		case config.SourceSummary:
+			fetcher := psi.NewFetcher(kubeClient.Clientset())
+			fetcher.IncludePods = cfg.PodAnalysis.Enabled || cfg.Eviction.Enabled
+			sources = append(sources, fetcher)
+		case config.SourcePrometheus:
+			prom := cfg.Sources.Prometheus
+			sources = append(sources, psi.NewPrometheusSource(prom.URL, prom.NodeLabel, prom.Timeout))
+		case config.SourceLastKnown:
+			lastKnownMaxAge = cfg.Sources.LastKnownMaxAge
+		}
+	}
+
+	if len(sources) == 1 && lastKnownMaxAge == 0 {
+		return sources[0]
+	}
+	return psi.NewCompositeSource(sources, lastKnownMaxAge)
 }
 
-func runWithLeaderElection(ctx context.Context, cancel context.CancelFunc, cfg *config.Config, kubeClient *kubernetes.Client, ctrl *controller.Controller, logger *log.Logger) {
+func runWithLeaderElection(ctx context.Context, cancel context.CancelFunc, cfg *config.Config, kubeClient *kubernetes.Client, ctrl *controller.Controller, logger *slog.Logger) {
 	id, err := os.Hostname()
 	if err != nil {
-		logger.Fatalf("Failed to get hostname for leader election identity: %v", err)
+		fatal(logger, "Failed to get hostname for leader election identity", logging.Err(err))
 	}
 
 	lock := &resourcelock.LeaseLock{
@@ -75,18 +224,18 @@ func runWithLeaderElection(ctx context.Context, cancel context.CancelFunc, cfg *
 		RetryPeriod:     cfg.LeaderElection.RetryPeriod,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(ctx context.Context) {
-				logger.Println("Acquired leadership, starting controller...")
+				logger.Info("Acquired leadership, starting controller")
 				ctrl.Run(ctx)
 			},
 			OnStoppedLeading: func() {
-				logger.Println("Lost leadership, shutting down...")
+				logger.Info("Lost leadership, shutting down")
 				cancel()
 			},
 			OnNewLeader: func(identity string) {
 				if identity == id {
 					return
 				}
-				logger.Printf("New leader elected: %s", identity)
+				logger.Info("New leader elected", slog.String("identity", identity))
 			},
 		},
 	})
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/simulate"
+)
+
+func runRecord(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("kube-dethrottler record", flag.ContinueOnError)
+	client := addClientFlags(flags)
+	tracePath := flags.String("trace", "", "Path of the trace file to write, or - for stdout.")
+	interval := flags.Duration("interval", 0, "Time between samples. Defaults to pollInterval.")
+	duration := flags.Duration("duration", 0, "Stop recording after this long. Records until interrupted if 0.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *tracePath == "" {
+		return errors.New("--trace is required")
+	}
+
+	cfg, kubeClient, err := client.load()
+	if err != nil {
+		return err
+	}
+	if *interval == 0 {
+		*interval = cfg.PollInterval
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("invalid interval: %s", *interval)
+	}
+
+	out := stdout
+	if *tracePath != "-" {
+		// #nosec G304 -- Writing the file named on the command line is the point
+		f, err := os.Create(*tracePath)
+		if err != nil {
+			return fmt.Errorf("failed to create trace file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	buf := bufio.NewWriter(out)
+	trace := simulate.NewTraceWriter(buf)
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	logger := commandLogger()
+	source := buildPSISource(cfg, kubeClient)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		if err := simulate.Record(ctx, kubeClient, source, cfg.NodeFilter, time.Now(), trace); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			logger.Warn("Failed to record samples", logging.Err(err))
+		}
+		// Flush every round, so that an interrupted recording is usable.
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("failed to write trace: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return buf.Flush()
+		case <-ticker.C:
+		}
+	}
+	return buf.Flush()
+}
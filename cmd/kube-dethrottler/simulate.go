@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/simulate"
+)
+
+func runSimulate(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("kube-dethrottler simulate", flag.ContinueOnError)
+	tracePath := flags.String("trace", "", "Path of a trace written by record.")
+	configFile := flags.String("config", defaultConfigFile, "Path of the configuration to simulate.")
+	flapWindow := flags.Duration("flap-window", simulate.DefaultFlapWindow, "A node tainted again this soon after its release counts as a flap.")
+	output := flags.String("output", outputTable, "Output format: table, json or yaml.")
+	flags.StringVar(output, "o", outputTable, "Shorthand for --output.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *tracePath == "" {
+		return errors.New("--trace is required")
+	}
+
+	cfg, err := config.LoadConfig(*configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration from %s: %w", *configFile, err)
+	}
+	// #nosec G304 -- Reading the file named on the command line is the point
+	f, err := os.Open(*tracePath)
+	if err != nil {
+		return fmt.Errorf("failed to open trace: %w", err)
+	}
+	defer f.Close()
+	trace, err := simulate.ReadTrace(f)
+	if err != nil {
+		return fmt.Errorf("invalid trace %s: %w", *tracePath, err)
+	}
+
+	result, err := simulate.Run(ctx, cfg, trace, simulate.Options{FlapWindow: *flapWindow}, commandLogger())
+	if err != nil {
+		return err
+	}
+	return writeOutput(stdout, *output, result, func(w io.Writer) {
+		writeTimeline(w, result)
+	})
+}
+
+// writeTimeline writes the timeline of a simulation followed by its
+// summary.
+func writeTimeline(w io.Writer, result *simulate.Result) {
+	fmt.Fprintln(w, "TIME\tNODE\tEVENT\tREASON")
+	for _, e := range result.Timeline {
+		kind := e.Kind
+		if e.Flap {
+			kind += " (flap)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Time.Format(time.RFC3339), e.Node, kind, orDash(e.Reason))
+	}
+
+	s := result.Summary
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Simulated:\t%s, %d polls of %d nodes\n", s.End.Sub(s.Start), s.Polls, s.Nodes)
+	fmt.Fprintf(w, "Taints:\t%d (%d flaps), %d releases\n", s.Taints, s.Flaps, s.Releases)
+	fmt.Fprintf(w, "Tainted node-minutes:\t%.1f\n", s.TaintedNodeMinutes)
+	fmt.Fprintf(w, "Max tainted nodes:\t%d\n", s.MaxTaintedNodes)
+	if s.Evictions > 0 {
+		fmt.Fprintf(w, "Evictions:\t%d\n", s.Evictions)
+	}
+}
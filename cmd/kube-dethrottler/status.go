@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Fedosin/kube-dethrottler/internal/actuator"
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+const defaultConfigFile = "/etc/kube-dethrottler/config.yaml"
+
+// Output formats of the subcommands.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// clientFlags are the flags of the subcommands that talk to the cluster.
+type clientFlags struct {
+	config     *string
+	kubeconfig *string
+}
+
+func addClientFlags(flags *flag.FlagSet) *clientFlags {
+	return &clientFlags{
+		config:     flags.String("config", defaultConfigFile, "Path to the configuration file."),
+		kubeconfig: flags.String("kubeconfig", "", "Path to a kubeconfig file. Overrides kubeconfigPath; the in-cluster configuration takes precedence."),
+	}
+}
+
+// load loads the configuration and creates a Kubernetes client the same
+// way the controller does.
+func (f *clientFlags) load() (*config.Config, *kubernetes.Client, error) {
+	cfg, err := config.LoadConfig(*f.config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration from %s: %w", *f.config, err)
+	}
+	if *f.kubeconfig != "" {
+		cfg.KubeconfigPath = *f.kubeconfig
+	}
+	client, err := kubernetes.NewClient(cfg.KubeconfigPath, commandLogger())
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, client, nil
+}
+
+// commandLogger returns the logger of the subcommands, which keeps stdout
+// for their output.
+func commandLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+}
+
+// writeOutput writes v as JSON or YAML, or calls table with a tabwriter.
+func writeOutput(w io.Writer, format string, v any, table func(w io.Writer)) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		table(tw)
+		return tw.Flush()
+	default:
+		return fmt.Errorf("invalid output format: %s. Must be one of: table, json, yaml", format)
+	}
+}
+
+// nodeStatus describes a node under pressure.
+type nodeStatus struct {
+	// TaintedSince is when the pressure began, from the pressure
+	// annotation, or the taint's timeAdded.
+	TaintedSince *time.Time   `json:"taintedSince,omitempty"`
+	PSI          *psi.NodePSI `json:"psi,omitempty"`
+	Node         string       `json:"node"`
+	Taint        string       `json:"taint,omitempty"`
+	Resource     string       `json:"resource,omitempty"`
+	Reason       string       `json:"reason,omitempty"`
+	TopPods      string       `json:"topPods,omitempty"`
+	PSIError     string       `json:"psiError,omitempty"`
+}
+
+func runStatus(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("kube-dethrottler status", flag.ContinueOnError)
+	client := addClientFlags(flags)
+	output := flags.String("output", outputTable, "Output format: table, json or yaml.")
+	flags.StringVar(output, "o", outputTable, "Shorthand for --output.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, kubeClient, err := client.load()
+	if err != nil {
+		return err
+	}
+	statuses, err := collectStatus(ctx, cfg, kubeClient, psi.NewFetcher(kubeClient.Clientset()))
+	if err != nil {
+		return err
+	}
+	return writeOutput(stdout, *output, statuses, func(w io.Writer) {
+		fmt.Fprintln(w, "NODE\tTAINT\tSINCE\tRESOURCE\tCPU\tMEMORY\tIO\tREASON")
+		for _, s := range statuses {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.Node, orDash(s.Taint), age(s.TaintedSince),
+				orDash(s.Resource), pressure(s.PSI, psi.ResourceCPU), pressure(s.PSI, psi.ResourceMemory),
+				pressure(s.PSI, psi.ResourceIO), orDash(s.Reason))
+		}
+	})
+}
+
+// collectStatus lists the nodes matching cfg.NodeFilter that are under
+// pressure, i.e. that carry cfg.TaintKey or actuator.ReasonAnnotation,
+// along with the persisted pressure annotations and their current PSI.
+func collectStatus(ctx context.Context, cfg *config.Config, client kubernetes.KubeClientInterface, source psi.Source) ([]nodeStatus, error) {
+	nodes, err := client.ListNodes(ctx, cfg.NodeFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []nodeStatus{}
+	for _, node := range nodes {
+		s := nodeStatus{Node: node.Name}
+		if i := slices.IndexFunc(node.Taints, func(t corev1.Taint) bool { return t.Key == cfg.TaintKey }); i >= 0 {
+			taint := node.Taints[i]
+			s.Taint = taint.Key + ":" + string(taint.Effect)
+			if taint.TimeAdded != nil {
+				s.TaintedSince = &taint.TimeAdded.Time
+			}
+		}
+		a, annotated := pressureAnnotation(node.Annotations, actuator.ReasonAnnotation, cfg.NodeAnnotation.Key)
+		if s.Taint == "" && !annotated {
+			continue
+		}
+		if annotated {
+			s.TaintedSince, s.Resource, s.Reason = &a.Since, a.Resource, a.Reason
+		}
+		s.TopPods = node.Annotations[cfg.PodAnalysis.AnnotationKey]
+
+		sample, err := source.FetchNodePSI(ctx, node.Name)
+		if err != nil {
+			s.PSIError = err.Error()
+		} else {
+			sample.Pods = nil
+			s.PSI = sample
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// pressureAnnotation decodes the first of the keys annotated with a
+// PressureAnnotation. Nodes put under pressure by earlier versions only
+// carry the annotation actuator's.
+func pressureAnnotation(annotations map[string]string, keys ...string) (actuator.PressureAnnotation, bool) {
+	for _, key := range keys {
+		var a actuator.PressureAnnotation
+		if raw, ok := annotations[key]; ok && json.Unmarshal([]byte(raw), &a) == nil {
+			return a, true
+		}
+	}
+	return actuator.PressureAnnotation{}, false
+}
+
+func age(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return time.Since(*t).Round(time.Second).String()
+}
+
+// pressure formats the some and full avg10 of a resource, e.g. "41.20/3.10".
+func pressure(sample *psi.NodePSI, resource string) string {
+	if sample == nil || sample.IsMissing(resource) {
+		return "-"
+	}
+	p := sample.ForResource(resource)
+	return fmt.Sprintf("%.2f/%.2f", p.Some.Avg10, p.Full.Avg10)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
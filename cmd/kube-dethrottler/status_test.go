@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Fedosin/kube-dethrottler/internal/actuator"
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+type fakeKubeClient struct {
+	kubernetes.KubeClientInterface
+	nodes []kubernetes.NodeInfo
+}
+
+func (f *fakeKubeClient) ListNodes(_ context.Context, _ string) ([]kubernetes.NodeInfo, error) {
+	return f.nodes, nil
+}
+
+type fakeSource map[string]*psi.NodePSI
+
+func (f fakeSource) Name() string { return "fake" }
+
+func (f fakeSource) FetchNodePSI(_ context.Context, nodeName string) (*psi.NodePSI, error) {
+	if sample, ok := f[nodeName]; ok {
+		return sample, nil
+	}
+	return nil, errors.New("summary unavailable")
+}
+
+func statusConfig() *config.Config {
+	return &config.Config{
+		TaintKey:       "kube-dethrottler/high-load",
+		NodeAnnotation: config.NodeAnnotation{Key: "kube-dethrottler.io/pressure"},
+		PodAnalysis:    config.PodAnalysis{AnnotationKey: "kube-dethrottler.io/top-pods"},
+	}
+}
+
+func TestCollectStatus(t *testing.T) {
+	taint := corev1.Taint{Key: "kube-dethrottler/high-load", Effect: corev1.TaintEffectNoSchedule}
+	client := &fakeKubeClient{nodes: []kubernetes.NodeInfo{
+		{Name: "node-1", Taints: []corev1.Taint{taint}, Annotations: map[string]string{
+			"kube-dethrottler.io/pressure": `{"since":"2026-10-18T09:00:00Z","resource":"cpu","reason":"cpu.some.avg10 61.20 > 50.00"}`,
+			"kube-dethrottler.io/top-pods": "batch/noisy(cpu=55.00)",
+		}},
+		{Name: "node-2"},
+		{Name: "node-3", Taints: []corev1.Taint{{Key: "other", Effect: corev1.TaintEffectNoSchedule}, taint}},
+		// Put under pressure by actuators other than the taint.
+		{Name: "node-4", Annotations: map[string]string{
+			actuator.ReasonAnnotation:      `{"since":"2026-10-18T10:00:00Z","resource":"io","reason":"io.full.avg60 40.00 > 20.00"}`,
+			"kube-dethrottler.io/pressure": `{"since":"2026-10-18T08:00:00Z","resource":"cpu","reason":"stale"}`,
+		}},
+	}}
+	source := fakeSource{"node-1": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 61.2}}}}
+
+	statuses, err := collectStatus(context.Background(), statusConfig(), client, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 3 || statuses[0].Node != "node-1" || statuses[1].Node != "node-3" || statuses[2].Node != "node-4" {
+		t.Fatalf("collectStatus() = %+v, want node-1, node-3 and node-4", statuses)
+	}
+
+	s := statuses[0]
+	wantSince := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	if s.TaintedSince == nil || !s.TaintedSince.Equal(wantSince) || s.Resource != "cpu" || s.TopPods != "batch/noisy(cpu=55.00)" {
+		t.Errorf("node-1 status = %+v, want the persisted annotations", s)
+	}
+	if s.PSI == nil || s.PSI.CPU.Some.Avg10 != 61.2 {
+		t.Errorf("node-1 PSI = %+v, want the current sample", s.PSI)
+	}
+	if statuses[1].PSIError == "" || statuses[1].TaintedSince != nil {
+		t.Errorf("node-3 status = %+v, want a PSI error and no start time", statuses[1])
+	}
+	if s := statuses[2]; s.Taint != "" || s.Resource != "io" || s.Reason != "io.full.avg60 40.00 > 20.00" {
+		t.Errorf("node-4 status = %+v, want the reason annotation", s)
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	statuses := []nodeStatus{{Node: "node-1", Taint: "kube-dethrottler/high-load:NoSchedule", Resource: "cpu",
+		PSI: &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: 61.2}}}}}
+	table := func(w io.Writer) {
+		for _, s := range statuses {
+			fmt.Fprintf(w, "%s\t%s\n", s.Node, pressure(s.PSI, psi.ResourceCPU))
+		}
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{outputTable, "node-1   61.20/0.00"},
+		{outputJSON, `"node": "node-1"`},
+		{outputYAML, "- node: node-1"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeOutput(&buf, tt.format, statuses, table); err != nil {
+			t.Fatalf("writeOutput(%s) error = %v", tt.format, err)
+		}
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("writeOutput(%s) = %q, want it to contain %q", tt.format, buf.String(), tt.want)
+		}
+	}
+
+	if err := writeOutput(&bytes.Buffer{}, "xml", statuses, table); err == nil {
+		t.Error("writeOutput(xml) error = nil, want an invalid format error")
+	}
+}
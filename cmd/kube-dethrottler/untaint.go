@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Fedosin/kube-dethrottler/internal/actuator"
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/controller"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+)
+
+func runPause(ctx context.Context, args []string, stdout io.Writer) error {
+	return setPaused(ctx, "kube-dethrottler pause", args, stdout, true)
+}
+
+func runResume(ctx context.Context, args []string, stdout io.Writer) error {
+	return setPaused(ctx, "kube-dethrottler resume", args, stdout, false)
+}
+
+// setPaused flips the pause switch read by the running controller.
+func setPaused(ctx context.Context, name string, args []string, stdout io.Writer, paused bool) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	client := addClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, kubeClient, err := client.load()
+	if err != nil {
+		return err
+	}
+	p := cfg.Pause
+	if !p.Enabled {
+		return fmt.Errorf("the pause switch is disabled; set pause.enabled in the configuration")
+	}
+	if err := kubeClient.SetConfigMapValue(ctx, p.Namespace, p.ConfigMap, config.PauseKey, strconv.FormatBool(paused)); err != nil {
+		return err
+	}
+	state := "resumed"
+	if paused {
+		state = "paused"
+	}
+	fmt.Fprintf(stdout, "kube-dethrottler %s (configmap %s/%s)\n", state, p.Namespace, p.ConfigMap)
+	return nil
+}
+
+func runUntaintAll(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("kube-dethrottler untaint-all", flag.ContinueOnError)
+	client := addClientFlags(flags)
+	all := flags.Bool("all", false, "Remove every taint with the configured key, whatever its value and effect, not only the controller's.")
+	dryRun := flags.Bool("dry-run", false, "List the taints that would be removed without removing them.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, kubeClient, err := client.load()
+	if err != nil {
+		return err
+	}
+	return untaintAll(ctx, cfg, kubeClient, stdout, *all, *dryRun)
+}
+
+// untaintAll removes the controller's taints from the nodes matching
+// cfg.NodeFilter, and reverts its other actions: the label, condition and
+// annotation actuators, the reason and top pods annotations, and the
+// scale-down annotation it owns. With all set, any taint with
+// cfg.TaintKey is removed. It goes on after a failure and reports the
+// failures at the end.
+func untaintAll(ctx context.Context, cfg *config.Config, client kubernetes.KubeClientInterface, stdout io.Writer, all, dryRun bool) error {
+	nodes, err := client.ListNodes(ctx, cfg.NodeFilter)
+	if err != nil {
+		return err
+	}
+
+	acts := nodeActuators(cfg, client)
+	removed, failed := 0, 0
+	for _, node := range nodes {
+		for _, taint := range node.Taints {
+			if !ownedTaint(cfg, taint, all) {
+				continue
+			}
+			desc := fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect)
+			if dryRun {
+				fmt.Fprintf(stdout, "%s: would remove %s\n", node.Name, desc)
+				removed++
+				continue
+			}
+			if err := client.RemoveTaint(ctx, node.Name, taint.Key, string(taint.Effect)); err != nil {
+				fmt.Fprintf(stdout, "%s: failed to remove %s: %v\n", node.Name, desc, err)
+				failed++
+				continue
+			}
+			fmt.Fprintf(stdout, "%s: removed %s\n", node.Name, desc)
+			removed++
+		}
+		r, f := revertActions(ctx, cfg, client, acts, node, stdout, dryRun)
+		removed, failed = removed+r, failed+f
+	}
+
+	if !dryRun && removed > 0 {
+		fmt.Fprintln(stdout, "Unless paused, the controller reapplies the actions to nodes still under pressure.")
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to revert %d actions", failed)
+	}
+	return nil
+}
+
+// nodeActuators returns the label, condition and annotation actuators used
+// by cfg.Actuators or a profile. Webhook receivers are not notified.
+func nodeActuators(cfg *config.Config, client kubernetes.KubeClientInterface) []actuator.Actuator {
+	names := slices.Clone(cfg.Actuators)
+	for _, p := range cfg.Profiles {
+		names = append(names, p.Actuators...)
+	}
+	var acts []actuator.Actuator
+	if slices.Contains(names, config.ActuatorLabel) {
+		acts = append(acts, actuator.NewLabel(client, cfg.NodeLabel.Key))
+	}
+	if slices.Contains(names, config.ActuatorCondition) {
+		acts = append(acts, actuator.NewCondition(client, cfg.NodeCondition.Type))
+	}
+	if slices.Contains(names, config.ActuatorAnnotation) {
+		acts = append(acts, actuator.NewAnnotation(client, cfg.NodeAnnotation.Key))
+	}
+	return acts
+}
+
+// revertActions reverts the actions other than taints in place on a node
+// and returns how many were reverted and how many failed.
+func revertActions(ctx context.Context, cfg *config.Config, client kubernetes.KubeClientInterface, acts []actuator.Actuator, node kubernetes.NodeInfo, stdout io.Writer, dryRun bool) (reverted, failed int) {
+	d := actuator.Decision{Time: time.Now(), Reason: "by untaint-all"}
+	for _, a := range acts {
+		applied, err := a.(actuator.Observer).Applied(ctx, node)
+		if err != nil || !applied {
+			continue
+		}
+		if dryRun {
+			fmt.Fprintf(stdout, "%s: would revert %s\n", node.Name, a.Describe())
+			reverted++
+			continue
+		}
+		if err := a.OnRecovered(ctx, node, d); err != nil {
+			fmt.Fprintf(stdout, "%s: failed to revert %s: %v\n", node.Name, a.Describe(), err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: reverted %s\n", node.Name, a.Describe())
+		reverted++
+	}
+
+	keys := []string{actuator.ReasonAnnotation, cfg.PodAnalysis.AnnotationKey}
+	if node.Annotations[controller.ScaleDownOwnerAnnotation] != "" {
+		keys = append(keys, controller.ScaleDownDisabledAnnotation, controller.ScaleDownOwnerAnnotation)
+	}
+	patch := map[string]*string{}
+	for _, key := range keys {
+		if _, ok := node.Annotations[key]; ok {
+			patch[key] = nil
+		}
+	}
+	if len(patch) == 0 {
+		return reverted, failed
+	}
+	desc := "annotations " + strings.Join(slices.Sorted(maps.Keys(patch)), ", ")
+	if dryRun {
+		fmt.Fprintf(stdout, "%s: would remove %s\n", node.Name, desc)
+		return reverted + 1, failed
+	}
+	if err := client.PatchNodeAnnotations(ctx, node.Name, patch); err != nil {
+		fmt.Fprintf(stdout, "%s: failed to remove %s: %v\n", node.Name, desc, err)
+		return reverted, failed + 1
+	}
+	fmt.Fprintf(stdout, "%s: removed %s\n", node.Name, desc)
+	return reverted + 1, failed
+}
+
+// ownedTaint reports whether taint was applied by the controller: it has
+// the configured key and effect, and the controller's value. With all
+// set, only the key must match.
+func ownedTaint(cfg *config.Config, taint corev1.Taint, all bool) bool {
+	if taint.Key != cfg.TaintKey {
+		return false
+	}
+	return all || (string(taint.Effect) == cfg.TaintEffect && taint.Value == actuator.TaintValue)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Fedosin/kube-dethrottler/internal/actuator"
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/controller"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+)
+
+type untaintClient struct {
+	fakeKubeClient
+	removed []string
+	// reverted records the other changes, e.g. "node-1/label key=none".
+	reverted []string
+}
+
+func (u *untaintClient) RemoveTaint(_ context.Context, nodeName, taintKey, taintEffect string) error {
+	u.removed = append(u.removed, nodeName+"/"+taintKey+":"+taintEffect)
+	return nil
+}
+
+func (u *untaintClient) SetNodeLabel(_ context.Context, nodeName, key, value string) error {
+	u.reverted = append(u.reverted, nodeName+"/label "+key+"="+value)
+	return nil
+}
+
+func (u *untaintClient) SetNodeCondition(_ context.Context, nodeName, conditionType string, status bool, _, _ string) error {
+	u.reverted = append(u.reverted, fmt.Sprintf("%s/condition %s=%v", nodeName, conditionType, status))
+	return nil
+}
+
+func (u *untaintClient) RemoveNodeAnnotation(_ context.Context, nodeName, key string) error {
+	u.reverted = append(u.reverted, nodeName+"/annotation "+key)
+	return nil
+}
+
+func (u *untaintClient) PatchNodeAnnotations(_ context.Context, nodeName string, annotations map[string]*string) error {
+	u.reverted = append(u.reverted, nodeName+"/annotations "+strings.Join(slices.Sorted(maps.Keys(annotations)), ","))
+	return nil
+}
+
+func TestUntaintAll(t *testing.T) {
+	key := "kube-dethrottler/high-load"
+	nodes := []kubernetes.NodeInfo{
+		{Name: "node-1", Taints: []corev1.Taint{{Key: key, Value: "high-load", Effect: corev1.TaintEffectNoSchedule}}},
+		{Name: "node-2", Taints: []corev1.Taint{{Key: key, Value: "manual", Effect: corev1.TaintEffectNoSchedule}}},
+		{Name: "node-3", Taints: []corev1.Taint{{Key: key, Value: "high-load", Effect: corev1.TaintEffectNoExecute}}},
+		{Name: "node-4", Taints: []corev1.Taint{{Key: "other", Value: "high-load", Effect: corev1.TaintEffectNoSchedule}}},
+	}
+
+	tests := []struct {
+		name   string
+		all    bool
+		dryRun bool
+		want   []string
+	}{
+		{name: "controller taints", want: []string{"node-1/" + key + ":NoSchedule"}},
+		{name: "all taints with the key", all: true, want: []string{
+			"node-1/" + key + ":NoSchedule", "node-2/" + key + ":NoSchedule", "node-3/" + key + ":NoExecute",
+		}},
+		{name: "dry run", dryRun: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := statusConfig()
+			cfg.TaintEffect = "NoSchedule"
+			client := &untaintClient{fakeKubeClient: fakeKubeClient{nodes: nodes}}
+			var out bytes.Buffer
+			if err := untaintAll(context.Background(), cfg, client, &out, tt.all, tt.dryRun); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(client.removed, tt.want) {
+				t.Errorf("removed %v, want %v", client.removed, tt.want)
+			}
+			if tt.dryRun && !bytes.Contains(out.Bytes(), []byte("node-1: would remove "+key+"=high-load:NoSchedule")) {
+				t.Errorf("dry run output = %q, want the taint of node-1", out.String())
+			}
+		})
+	}
+}
+
+func TestUntaintAll_OtherActions(t *testing.T) {
+	pressure := `{"since":"2026-10-18T09:00:00Z","resource":"cpu","reason":"cpu.some.avg10 61.20 > 50.00"}`
+	nodes := []kubernetes.NodeInfo{
+		{
+			Name:       "node-1",
+			Labels:     map[string]string{"kube-dethrottler.io/pressure": "cpu"},
+			Conditions: map[string]bool{"PSIPressure": true},
+			Annotations: map[string]string{
+				"kube-dethrottler.io/pressure":         pressure,
+				actuator.ReasonAnnotation:              pressure,
+				"kube-dethrottler.io/top-pods":         "batch/noisy(cpu=55.00)",
+				controller.ScaleDownDisabledAnnotation: "true",
+				controller.ScaleDownOwnerAnnotation:    "true",
+			},
+		},
+		// Healthy, with a scale-down annotation set by someone else.
+		{
+			Name:        "node-2",
+			Labels:      map[string]string{"kube-dethrottler.io/pressure": actuator.LabelNone},
+			Conditions:  map[string]bool{"PSIPressure": false},
+			Annotations: map[string]string{controller.ScaleDownDisabledAnnotation: "true"},
+		},
+	}
+	cfg := statusConfig()
+	cfg.Actuators = []string{config.ActuatorTaint}
+	cfg.Profiles = []config.Profile{{Name: "all", NodeSelector: "pool=batch",
+		Actuators: []string{config.ActuatorLabel, config.ActuatorCondition, config.ActuatorAnnotation}}}
+	cfg.NodeLabel.Key = "kube-dethrottler.io/pressure"
+	cfg.NodeCondition.Type = "PSIPressure"
+
+	client := &untaintClient{fakeKubeClient: fakeKubeClient{nodes: nodes}}
+	var out bytes.Buffer
+	if err := untaintAll(context.Background(), cfg, client, &out, false, false); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"node-1/label kube-dethrottler.io/pressure=none",
+		"node-1/condition PSIPressure=false",
+		"node-1/annotation kube-dethrottler.io/pressure",
+		"node-1/annotations " + strings.Join(slices.Sorted(slices.Values([]string{
+			actuator.ReasonAnnotation, "kube-dethrottler.io/top-pods",
+			controller.ScaleDownDisabledAnnotation, controller.ScaleDownOwnerAnnotation,
+		})), ","),
+	}
+	if !slices.Equal(client.reverted, want) {
+		t.Errorf("reverted %v, want %v", client.reverted, want)
+	}
+
+	client = &untaintClient{fakeKubeClient: fakeKubeClient{nodes: nodes}}
+	out.Reset()
+	if err := untaintAll(context.Background(), cfg, client, &out, false, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.reverted) != 0 || !strings.Contains(out.String(), "node-1: would revert condition PSIPressure") {
+		t.Errorf("dry run reverted %v with output %q, want only the listing", client.reverted, out.String())
+	}
+}
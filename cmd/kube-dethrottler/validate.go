@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+)
+
+// Schemas printed by validate-config --schema.
+const (
+	schemaConfig = "config"
+	schemaHelm   = "helm"
+)
+
+func runValidateConfig(_ context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("kube-dethrottler validate-config", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: kube-dethrottler validate-config [flags] [file...]")
+		flags.PrintDefaults()
+	}
+	helmValues := flags.Bool("helm-values", false, "The files are Helm values; validate their config value.")
+	schema := flags.String("schema", "", "Print the JSON Schema of the configuration (config) or of the chart values (helm) instead.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	switch *schema {
+	case "":
+	case schemaConfig:
+		return writeSchema(stdout, config.Schema())
+	case schemaHelm:
+		return writeSchema(stdout, config.HelmValuesSchema())
+	default:
+		return fmt.Errorf("invalid schema: %s. Must be one of: config, helm", *schema)
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{defaultConfigFile}
+	}
+	parse := config.Parse
+	if *helmValues {
+		parse = config.ParseHelmValues
+	}
+	invalid := 0
+	for _, file := range files {
+		if err := validateFile(file, parse); err != nil {
+			fmt.Fprintf(stdout, "%s: %v\n", file, err)
+			invalid++
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: valid\n", file)
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d files are invalid", invalid, len(files))
+	}
+	return nil
+}
+
+func validateFile(file string, parse func([]byte) (*config.Config, error)) error {
+	// #nosec G304 -- Reading the files named on the command line is the point
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	_, err = parse(data)
+	return err
+}
+
+func writeSchema(w io.Writer, schema map[string]any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(schema)
+}
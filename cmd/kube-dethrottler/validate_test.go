@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+)
+
+// TestPublishedSchemas fails when the committed schemas are out of date;
+// regenerate them with make schema.
+func TestPublishedSchemas(t *testing.T) {
+	for file, schema := range map[string]map[string]any{
+		"../../config.schema.json":                         config.Schema(),
+		"../../charts/kube-dethrottler/values.schema.json": config.HelmValuesSchema(),
+	} {
+		var want bytes.Buffer
+		if err := writeSchema(&want, schema); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Errorf("%s is out of date; run make schema", file)
+		}
+	}
+}
+
+func TestChartValuesAreValid(t *testing.T) {
+	if err := validateFile("../../charts/kube-dethrottler/values.yaml", config.ParseHelmValues); err != nil {
+		t.Errorf("chart values: %v", err)
+	}
+}
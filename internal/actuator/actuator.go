@@ -0,0 +1,97 @@
+// Package actuator implements the actions taken on a node when it comes
+// under pressure and when it recovers: tainting it, labelling or
+// annotating it, setting a node condition or calling a webhook.
+package actuator
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+)
+
+// Names of the built-in actuators, as used in the configuration.
+const (
+	NameTaint      = "taint"
+	NameCondition  = "condition"
+	NameLabel      = "label"
+	NameAnnotation = "annotation"
+	NameWebhook    = "webhook"
+)
+
+// ResourceOther is the Decision resource when pressure was signalled by
+// something not tied to a resource, e.g. a rule or missing data.
+const ResourceOther = "other"
+
+// Breach is a single threshold exceeded on a node.
+type Breach struct {
+	// Name is the dotted name of the breached signal, e.g. "cpu.some.avg10".
+	Name string
+	// Resource is the resource the signal is measured on, if any; Tier
+	// ("some" or "full") and Window are set for PSI thresholds.
+	Resource  string
+	Tier      string
+	Window    string
+	Value     float64
+	Threshold float64
+}
+
+// Decision describes why the controller acts on a node.
+type Decision struct {
+	Time time.Time
+	// Resource is the dominant pressure resource: cpu, memory, io or
+	// ResourceOther. It is empty on recovery.
+	Resource string
+	// Busiest is the resource with the highest some avg10 in the node's
+	// sample, cpu if none is above 0, for actions that must name a
+	// resource when Resource is ResourceOther. It is empty on recovery.
+	Busiest string
+	// Reason describes the breaches on pressure, or why the actions are
+	// released on recovery, e.g. "after pressure subsided".
+	Reason string
+	// Breaches lists the exceeded thresholds, the one that exceeds its
+	// threshold the most first.
+	Breaches []Breach
+	// TaintedNodes is the number of nodes under pressure after the
+	// transition.
+	TaintedNodes int
+}
+
+// Actuator reacts to a node entering and leaving pressure. Both calls
+// must be idempotent: the controller retries them on the next poll when
+// any actuator in the list fails.
+type Actuator interface {
+	// Name returns the actuator's configuration name, e.g. NameTaint.
+	Name() string
+	// Describe names the action for logs and Events, e.g.
+	// "taint kube-dethrottler/high-load:NoSchedule".
+	Describe() string
+	OnPressure(ctx context.Context, node kubernetes.NodeInfo, d Decision) error
+	OnRecovered(ctx context.Context, node kubernetes.NodeInfo, d Decision) error
+}
+
+// Observer is implemented by actuators whose action is visible on the
+// node, so that the controller can pick up nodes already under pressure
+// after a restart.
+type Observer interface {
+	Applied(ctx context.Context, node kubernetes.NodeInfo) (bool, error)
+}
+
+// Initializer is implemented by actuators that publish a neutral state on
+// nodes seen for the first time, e.g. a False condition.
+type Initializer interface {
+	Init(ctx context.Context, node kubernetes.NodeInfo) error
+}
+
+// Runner is implemented by actuators that work in the background, e.g.
+// to deliver notifications. Run returns once ctx is cancelled and pending
+// work is flushed.
+type Runner interface {
+	Run(ctx context.Context)
+}
+
+// Refresher is implemented by actuators whose action depends on the
+// decision and is updated on every poll while pressure lasts.
+type Refresher interface {
+	Refresh(ctx context.Context, node kubernetes.NodeInfo, d Decision) error
+}
@@ -0,0 +1,182 @@
+package actuator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/notify"
+)
+
+// fakeKubeClient records label and annotation writes. Calls to other
+// methods panic through the nil embedded interface.
+type fakeKubeClient struct {
+	kubernetes.KubeClientInterface
+	labels      map[string]string
+	annotations map[string]string
+}
+
+func newFakeKubeClient() *fakeKubeClient {
+	return &fakeKubeClient{labels: map[string]string{}, annotations: map[string]string{}}
+}
+
+func (f *fakeKubeClient) SetNodeLabel(_ context.Context, nodeName, key, value string) error {
+	f.labels[nodeName+"/"+key] = value
+	return nil
+}
+
+func (f *fakeKubeClient) SetNodeAnnotation(_ context.Context, nodeName, key, value string) error {
+	f.annotations[nodeName+"/"+key] = value
+	return nil
+}
+
+func (f *fakeKubeClient) RemoveNodeAnnotation(_ context.Context, nodeName, key string) error {
+	delete(f.annotations, nodeName+"/"+key)
+	return nil
+}
+
+func TestLabel(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeKubeClient()
+	label := NewLabel(client, "kube-dethrottler.io/pressure")
+	node := kubernetes.NodeInfo{Name: "node-1"}
+
+	if err := label.Init(ctx, node); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if got := client.labels["node-1/kube-dethrottler.io/pressure"]; got != LabelNone {
+		t.Errorf("label after Init() = %q, want %q", got, LabelNone)
+	}
+
+	if err := label.OnPressure(ctx, node, Decision{Resource: "cpu"}); err != nil {
+		t.Fatalf("OnPressure() error = %v", err)
+	}
+	node.Labels = map[string]string{"kube-dethrottler.io/pressure": "cpu"}
+	if applied, _ := label.Applied(ctx, node); !applied {
+		t.Error("Applied() = false for a node labelled with a resource")
+	}
+
+	delete(client.labels, "node-1/kube-dethrottler.io/pressure")
+	if err := label.Refresh(ctx, node, Decision{Resource: "cpu"}); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if _, written := client.labels["node-1/kube-dethrottler.io/pressure"]; written {
+		t.Error("Refresh() rewrote an unchanged label")
+	}
+	if err := label.Refresh(ctx, node, Decision{Resource: "io"}); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got := client.labels["node-1/kube-dethrottler.io/pressure"]; got != "io" {
+		t.Errorf("label after Refresh() = %q, want io", got)
+	}
+
+	// Pressure not tied to a resource, e.g. a rule, names the busiest one.
+	if err := label.OnPressure(ctx, node, Decision{Resource: ResourceOther, Busiest: "memory"}); err != nil {
+		t.Fatalf("OnPressure() error = %v", err)
+	}
+	if got := client.labels["node-1/kube-dethrottler.io/pressure"]; got != "memory" {
+		t.Errorf("label for a rule breach = %q, want the busiest resource memory", got)
+	}
+
+	if err := label.OnRecovered(ctx, node, Decision{}); err != nil {
+		t.Fatalf("OnRecovered() error = %v", err)
+	}
+	if got := client.labels["node-1/kube-dethrottler.io/pressure"]; got != LabelNone {
+		t.Errorf("label after OnRecovered() = %q, want %q", got, LabelNone)
+	}
+}
+
+func TestAnnotation(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeKubeClient()
+	annotation := NewAnnotation(client, "kube-dethrottler.io/pressure")
+	node := kubernetes.NodeInfo{Name: "node-1"}
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	err := annotation.OnPressure(ctx, node, Decision{Time: since, Resource: "memory", Reason: "memory.some.avg10 (40.00) > 20.00"})
+	if err != nil {
+		t.Fatalf("OnPressure() error = %v", err)
+	}
+	var got PressureAnnotation
+	if err := json.Unmarshal([]byte(client.annotations["node-1/kube-dethrottler.io/pressure"]), &got); err != nil {
+		t.Fatalf("annotation is not valid JSON: %v", err)
+	}
+	if !got.Since.Equal(since) || got.Resource != "memory" || !strings.Contains(got.Reason, "memory.some.avg10") {
+		t.Errorf("annotation = %+v", got)
+	}
+
+	node.Annotations = map[string]string{"kube-dethrottler.io/pressure": "{}"}
+	if applied, _ := annotation.Applied(ctx, node); !applied {
+		t.Error("Applied() = false for an annotated node")
+	}
+
+	if err := annotation.OnRecovered(ctx, node, Decision{}); err != nil {
+		t.Fatalf("OnRecovered() error = %v", err)
+	}
+	if _, exists := client.annotations["node-1/kube-dethrottler.io/pressure"]; exists {
+		t.Error("annotation not removed on recovery")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	received := make(chan notify.Batch, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var batch notify.Batch
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		received <- batch
+	}))
+	defer server.Close()
+
+	notifier, err := notify.NewWebhook(notify.WebhookConfig{URL: server.URL + "/hook", Timeout: time.Second}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	webhook := NewWebhook(notifier, server.URL+"/hook")
+	go webhook.Run(ctx)
+
+	node := kubernetes.NodeInfo{Name: "node-1"}
+	d := Decision{
+		Time:     time.Now(),
+		Resource: "cpu",
+		Reason:   "cpu.some.avg10 (50.00) > 25.00",
+		Breaches: []Breach{
+			{Name: "cpu.some.avg10", Resource: "cpu", Tier: "some", Window: "avg10", Value: 50, Threshold: 25},
+			{Name: "rule.busy", Value: 1},
+		},
+		TaintedNodes: 3,
+	}
+	if err := webhook.OnPressure(ctx, node, d); err != nil {
+		t.Fatalf("OnPressure() error = %v", err)
+	}
+
+	select {
+	case batch := <-received:
+		if len(batch.Events) != 1 {
+			t.Fatalf("got %d events, want 1", len(batch.Events))
+		}
+		e := batch.Events[0]
+		if e.Event != notify.EventPressure || e.Node != "node-1" || e.Resource != "cpu" || e.Tier != "some" ||
+			e.Window != "avg10" || e.Observed != 50 || e.Threshold != 25 || len(e.Breaches) != 2 {
+			t.Errorf("event = %+v", e)
+		}
+		if batch.TaintedNodes != 3 {
+			t.Errorf("taintedNodes = %d, want 3", batch.TaintedNodes)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not called")
+	}
+	if desc := webhook.Describe(); strings.Contains(desc, "/hook") {
+		t.Errorf("Describe() = %q, should not include the path", desc)
+	}
+}
@@ -0,0 +1,71 @@
+package actuator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+)
+
+var (
+	_ Actuator = (*Annotation)(nil)
+	_ Observer = (*Annotation)(nil)
+)
+
+// ReasonAnnotation is set by the controller on every node under pressure,
+// whatever its actuators, to a PressureAnnotation. The status command
+// reads it.
+const ReasonAnnotation = "kube-dethrottler.io/pressure-reason"
+
+// PressureAnnotation is the JSON value of ReasonAnnotation and of the
+// annotation set by the annotation actuator. It records when and why the
+// node came under pressure.
+type PressureAnnotation struct {
+	Since    time.Time `json:"since"`
+	Resource string    `json:"resource"`
+	Reason   string    `json:"reason"`
+}
+
+// Annotation annotates nodes under pressure with a PressureAnnotation and
+// removes the annotation on recovery.
+type Annotation struct {
+	client kubernetes.KubeClientInterface
+	key    string
+}
+
+// NewAnnotation creates an annotation actuator for the annotation key.
+func NewAnnotation(client kubernetes.KubeClientInterface, key string) *Annotation {
+	return &Annotation{client: client, key: key}
+}
+
+// Name returns NameAnnotation.
+func (a *Annotation) Name() string {
+	return NameAnnotation
+}
+
+// Describe returns e.g. "annotation kube-dethrottler.io/pressure".
+func (a *Annotation) Describe() string {
+	return "annotation " + a.key
+}
+
+// OnPressure sets the annotation.
+func (a *Annotation) OnPressure(ctx context.Context, node kubernetes.NodeInfo, d Decision) error {
+	value, err := json.Marshal(PressureAnnotation{Since: d.Time.UTC(), Resource: d.Resource, Reason: d.Reason})
+	if err != nil {
+		return fmt.Errorf("failed to encode annotation: %w", err)
+	}
+	return a.client.SetNodeAnnotation(ctx, node.Name, a.key, string(value))
+}
+
+// OnRecovered removes the annotation.
+func (a *Annotation) OnRecovered(ctx context.Context, node kubernetes.NodeInfo, _ Decision) error {
+	return a.client.RemoveNodeAnnotation(ctx, node.Name, a.key)
+}
+
+// Applied reports whether the node has the annotation.
+func (a *Annotation) Applied(_ context.Context, node kubernetes.NodeInfo) (bool, error) {
+	_, exists := node.Annotations[a.key]
+	return exists, nil
+}
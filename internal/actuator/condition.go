@@ -0,0 +1,67 @@
+package actuator
+
+import (
+	"context"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+)
+
+// Reasons reported on the node condition.
+const (
+	ConditionReasonHigh   = "PressureExceeded"
+	ConditionReasonNormal = "PressureNormal"
+)
+
+var (
+	_ Actuator    = (*Condition)(nil)
+	_ Observer    = (*Condition)(nil)
+	_ Initializer = (*Condition)(nil)
+)
+
+// Condition sets a condition in the status of nodes: True with the
+// breaches as message under pressure, False otherwise.
+type Condition struct {
+	client        kubernetes.KubeClientInterface
+	conditionType string
+}
+
+// NewCondition creates a condition actuator for the condition type.
+func NewCondition(client kubernetes.KubeClientInterface, conditionType string) *Condition {
+	return &Condition{client: client, conditionType: conditionType}
+}
+
+// Name returns NameCondition.
+func (c *Condition) Name() string {
+	return NameCondition
+}
+
+// Describe returns e.g. "condition PSIPressure".
+func (c *Condition) Describe() string {
+	return "condition " + c.conditionType
+}
+
+// OnPressure sets the condition to True.
+func (c *Condition) OnPressure(ctx context.Context, node kubernetes.NodeInfo, d Decision) error {
+	return c.client.SetNodeCondition(ctx, node.Name, c.conditionType, true, ConditionReasonHigh, d.Reason)
+}
+
+// OnRecovered sets the condition to False.
+func (c *Condition) OnRecovered(ctx context.Context, node kubernetes.NodeInfo, d Decision) error {
+	return c.client.SetNodeCondition(ctx, node.Name, c.conditionType, false,
+		ConditionReasonNormal, "Pressure actions released "+d.Reason)
+}
+
+// Applied reports whether the condition is True.
+func (c *Condition) Applied(_ context.Context, node kubernetes.NodeInfo) (bool, error) {
+	return node.Conditions[c.conditionType], nil
+}
+
+// Init publishes the condition as False on nodes without it, so that the
+// condition always reflects the latest evaluation.
+func (c *Condition) Init(ctx context.Context, node kubernetes.NodeInfo) error {
+	if _, exists := node.Conditions[c.conditionType]; exists {
+		return nil
+	}
+	return c.client.SetNodeCondition(ctx, node.Name, c.conditionType, false,
+		ConditionReasonNormal, "Pressure is below thresholds")
+}
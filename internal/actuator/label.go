@@ -0,0 +1,84 @@
+package actuator
+
+import (
+	"context"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+)
+
+// LabelNone is the label value of nodes that are not under pressure.
+const LabelNone = "none"
+
+var (
+	_ Actuator    = (*Label)(nil)
+	_ Observer    = (*Label)(nil)
+	_ Initializer = (*Label)(nil)
+	_ Refresher   = (*Label)(nil)
+)
+
+// Label sets a node label to the dominant pressure resource (cpu, memory
+// or io) under pressure and to LabelNone otherwise, for workloads that
+// avoid pressured nodes through node affinity. Pressure not tied to a
+// resource is labelled with the busiest one.
+type Label struct {
+	client kubernetes.KubeClientInterface
+	key    string
+}
+
+// NewLabel creates a label actuator for the label key.
+func NewLabel(client kubernetes.KubeClientInterface, key string) *Label {
+	return &Label{client: client, key: key}
+}
+
+// Name returns NameLabel.
+func (l *Label) Name() string {
+	return NameLabel
+}
+
+// Describe returns e.g. "label kube-dethrottler.io/pressure".
+func (l *Label) Describe() string {
+	return "label " + l.key
+}
+
+// OnPressure sets the label to the decision's resource.
+func (l *Label) OnPressure(ctx context.Context, node kubernetes.NodeInfo, d Decision) error {
+	return l.client.SetNodeLabel(ctx, node.Name, l.key, labelValue(d))
+}
+
+// OnRecovered sets the label to LabelNone.
+func (l *Label) OnRecovered(ctx context.Context, node kubernetes.NodeInfo, _ Decision) error {
+	return l.client.SetNodeLabel(ctx, node.Name, l.key, LabelNone)
+}
+
+// Applied reports whether the label names a resource.
+func (l *Label) Applied(_ context.Context, node kubernetes.NodeInfo) (bool, error) {
+	value := node.Labels[l.key]
+	return value != "" && value != LabelNone, nil
+}
+
+// Init labels nodes without the label with LabelNone, so that label
+// selectors can match nodes that are not under pressure.
+func (l *Label) Init(ctx context.Context, node kubernetes.NodeInfo) error {
+	if _, exists := node.Labels[l.key]; exists {
+		return nil
+	}
+	return l.client.SetNodeLabel(ctx, node.Name, l.key, LabelNone)
+}
+
+// Refresh follows the dominant resource while pressure lasts.
+func (l *Label) Refresh(ctx context.Context, node kubernetes.NodeInfo, d Decision) error {
+	value := labelValue(d)
+	if node.Labels[l.key] == value {
+		return nil
+	}
+	return l.client.SetNodeLabel(ctx, node.Name, l.key, value)
+}
+
+// labelValue returns the resource to label a node with: the dominant one,
+// or the busiest when no breach names one.
+func labelValue(d Decision) string {
+	if d.Resource == ResourceOther && d.Busiest != "" {
+		return d.Busiest
+	}
+	return d.Resource
+}
@@ -0,0 +1,54 @@
+package actuator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+)
+
+var (
+	_ Actuator = (*Taint)(nil)
+	_ Observer = (*Taint)(nil)
+)
+
+// TaintValue is the value of the taints applied by the controller, which
+// tells them apart from taints with the same key added by others.
+const TaintValue = "high-load"
+
+// Taint applies a taint to nodes under pressure.
+type Taint struct {
+	client kubernetes.KubeClientInterface
+	key    string
+	effect string
+}
+
+// NewTaint creates a taint actuator for the taint key and effect.
+func NewTaint(client kubernetes.KubeClientInterface, key, effect string) *Taint {
+	return &Taint{client: client, key: key, effect: effect}
+}
+
+// Name returns NameTaint.
+func (t *Taint) Name() string {
+	return NameTaint
+}
+
+// Describe returns e.g. "taint kube-dethrottler/high-load:NoSchedule".
+func (t *Taint) Describe() string {
+	return fmt.Sprintf("taint %s:%s", t.key, t.effect)
+}
+
+// OnPressure applies the taint.
+func (t *Taint) OnPressure(ctx context.Context, node kubernetes.NodeInfo, _ Decision) error {
+	return t.client.ApplyTaint(ctx, node.Name, t.key, TaintValue, t.effect)
+}
+
+// OnRecovered removes the taint.
+func (t *Taint) OnRecovered(ctx context.Context, node kubernetes.NodeInfo, _ Decision) error {
+	return t.client.RemoveTaint(ctx, node.Name, t.key, t.effect)
+}
+
+// Applied reports whether the node has the taint.
+func (t *Taint) Applied(ctx context.Context, node kubernetes.NodeInfo) (bool, error) {
+	return t.client.HasTaint(ctx, node.Name, t.key, t.effect)
+}
@@ -0,0 +1,91 @@
+package actuator
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/notify"
+)
+
+var (
+	_ Actuator = (*Webhook)(nil)
+	_ Runner   = (*Webhook)(nil)
+)
+
+// Webhook notifies an HTTP endpoint when a node comes under pressure and
+// when it recovers. Events are delivered in the background, so failures
+// are retried and logged by the notifier rather than returned.
+type Webhook struct {
+	notifier *notify.Webhook
+	url      string
+}
+
+// NewWebhook creates a webhook actuator delivering through notifier to
+// rawURL.
+func NewWebhook(notifier *notify.Webhook, rawURL string) *Webhook {
+	return &Webhook{notifier: notifier, url: rawURL}
+}
+
+// Name returns NameWebhook.
+func (w *Webhook) Name() string {
+	return NameWebhook
+}
+
+// Describe returns e.g. "webhook hooks.example.com", leaving out the
+// path and query, which may carry credentials.
+func (w *Webhook) Describe() string {
+	if u, err := url.Parse(w.url); err == nil {
+		return "webhook " + u.Host
+	}
+	return "webhook"
+}
+
+// OnPressure queues a pressure event.
+func (w *Webhook) OnPressure(_ context.Context, node kubernetes.NodeInfo, d Decision) error {
+	e := notify.Event{
+		Time:         d.Time.UTC(),
+		Event:        notify.EventPressure,
+		Node:         node.Name,
+		Resource:     d.Resource,
+		Reason:       d.Reason,
+		TaintedNodes: d.TaintedNodes,
+	}
+	if len(d.Breaches) > 0 {
+		b := d.Breaches[0]
+		e.Tier, e.Window, e.Observed, e.Threshold = b.Tier, b.Window, b.Value, b.Threshold
+	}
+	e.Breaches = d.NotifyBreaches()
+	w.notifier.Notify(e)
+	return nil
+}
+
+// OnRecovered queues a recovered event.
+func (w *Webhook) OnRecovered(_ context.Context, node kubernetes.NodeInfo, d Decision) error {
+	w.notifier.Notify(notify.Event{
+		Time:         d.Time.UTC(),
+		Event:        notify.EventRecovered,
+		Node:         node.Name,
+		Reason:       d.Reason,
+		TaintedNodes: d.TaintedNodes,
+	})
+	return nil
+}
+
+// NotifyBreaches returns the decision's breaches as reported in
+// notifications.
+func (d Decision) NotifyBreaches() []notify.Breach {
+	breaches := make([]notify.Breach, 0, len(d.Breaches))
+	for _, b := range d.Breaches {
+		breaches = append(breaches, notify.Breach{
+			Name: b.Name, Resource: b.Resource, Tier: b.Tier, Window: b.Window,
+			Observed: b.Value, Threshold: b.Threshold,
+		})
+	}
+	return breaches
+}
+
+// Run delivers the queued events until ctx is cancelled.
+func (w *Webhook) Run(ctx context.Context) {
+	w.notifier.Run(ctx)
+}
@@ -1,13 +1,25 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+	"github.com/Fedosin/kube-dethrottler/internal/rules"
 )
 
 // PSIAverages defines thresholds for the three PSI averaging windows.
@@ -24,11 +36,301 @@ type PSIPressure struct {
 	Full PSIAverages `yaml:"full"`
 }
 
-// PSIThresholds defines pressure thresholds for CPU, memory, and I/O.
+// ExtraSignals are non-PSI signals combined with the PSI thresholds.
+type ExtraSignals struct {
+	// MemoryAvailableBelow marks the node under pressure when the kubelet's
+	// memory.availableBytes drops below this quantity (e.g. "1Gi").
+	MemoryAvailableBelow string `yaml:"memoryAvailableBelow"`
+	// TaintOnConditions marks the node under pressure while any of these
+	// node conditions (e.g. MemoryPressure) is True.
+	TaintOnConditions []string `yaml:"taintOnConditions"`
+	// HoldOnConditions keeps an existing taint in place while any of these
+	// node conditions (e.g. DiskPressure) is True.
+	HoldOnConditions []string `yaml:"holdOnConditions"`
+	// FSAvailableBelowPercent marks the node under pressure when the node
+	// filesystem's available space drops below this percentage of capacity.
+	FSAvailableBelowPercent float64 `yaml:"fsAvailableBelowPercent"`
+}
+
+// MemoryAvailableBelowBytes returns MemoryAvailableBelow in bytes, or 0 if unset.
+func (s *ExtraSignals) MemoryAvailableBelowBytes() (int64, error) {
+	if s.MemoryAvailableBelow == "" {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(s.MemoryAvailableBelow)
+	if err != nil {
+		return 0, err
+	}
+	return q.Value(), nil
+}
+
+// Rule is a named CEL expression that marks the node under pressure when
+// it evaluates to true. See the rules package for the available variables.
+type Rule struct {
+	Name       string `yaml:"name"`
+	Expression string `yaml:"expression"`
+}
+
+// RiseTrigger fires when a PSI value climbed by more than RiseBy points
+// between the oldest and the newest of the last Polls samples.
+type RiseTrigger struct {
+	// Metric is the dotted PSI value, e.g. "cpu.some.avg10".
+	Metric string  `yaml:"metric"`
+	RiseBy float64 `yaml:"riseBy"`
+	Polls  int     `yaml:"polls"`
+}
+
+// DivergenceTrigger fires when the avg10 of a pressure category exceeds
+// Factor times its avg300, i.e. pressure is well above its recent baseline.
+// MinAvg10 ignores divergence while avg10 itself is still low.
+type DivergenceTrigger struct {
+	// Pressure is the resource and category, e.g. "memory.some".
+	Pressure string  `yaml:"pressure"`
+	Factor   float64 `yaml:"factor"`
+	MinAvg10 float64 `yaml:"minAvg10"`
+}
+
+// TrendTriggers react to how pressure changes rather than its level.
+type TrendTriggers struct {
+	Rise       []RiseTrigger       `yaml:"rise"`
+	Divergence []DivergenceTrigger `yaml:"divergence"`
+}
+
+// PSIThresholds defines pressure thresholds for CPU, memory, and I/O,
+// plus additional non-PSI signals, trend triggers and rule expressions.
 type PSIThresholds struct {
-	CPU    PSIPressure `yaml:"cpu"`
-	Memory PSIPressure `yaml:"memory"`
-	IO     PSIPressure `yaml:"io"`
+	CPU     PSIPressure   `yaml:"cpu"`
+	Memory  PSIPressure   `yaml:"memory"`
+	IO      PSIPressure   `yaml:"io"`
+	Signals ExtraSignals  `yaml:"signals"`
+	Trends  TrendTriggers `yaml:"trends"`
+	Rules   []Rule        `yaml:"rules"`
+}
+
+// Policies for handling nodes whose PSI data is missing or stale.
+const (
+	// MissingDataIgnore skips threshold checks for the affected resources.
+	MissingDataIgnore = "ignore"
+	// MissingDataBreach treats the affected resources as exceeding their thresholds.
+	MissingDataBreach = "breach"
+	// MissingDataUnknown marks the node unknown and leaves its taint untouched.
+	MissingDataUnknown = "unknown"
+)
+
+// MissingData configures how absent or stale PSI samples are handled.
+type MissingData struct {
+	Policy string `yaml:"policy"`
+	// MaxSampleAge is the maximum age of a kubelet sample before it is
+	// considered stale. A value of 0 disables the staleness check.
+	MaxSampleAge time.Duration `yaml:"maxSampleAge"`
+}
+
+// Names of the PSI sources accepted in Sources.Order.
+const (
+	SourceSummary    = "summary"
+	SourcePrometheus = "prometheus"
+	SourceLastKnown  = "lastKnown"
+)
+
+// PrometheusSource configures reading PSI from node_exporter series in Prometheus.
+type PrometheusSource struct {
+	URL string `yaml:"url"`
+	// NodeLabel is the series label holding the Kubernetes node name.
+	NodeLabel string        `yaml:"nodeLabel"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+// PSISources configures where PSI samples are read from. Sources in Order
+// are tried first to last until one succeeds; "lastKnown" reuses the
+// previous successful sample if it is younger than LastKnownMaxAge.
+type PSISources struct {
+	Prometheus      PrometheusSource `yaml:"prometheus"`
+	Order           []string         `yaml:"order"`
+	LastKnownMaxAge time.Duration    `yaml:"lastKnownMaxAge"`
+}
+
+// PodAnalysis configures reporting of the pods contributing the most
+// pressure on tainted nodes. It requires the summary source.
+type PodAnalysis struct {
+	AnnotationKey string `yaml:"annotationKey"`
+	TopN          int    `yaml:"topN"`
+	Enabled       bool   `yaml:"enabled"`
+}
+
+// Eviction configures the opt-in eviction of the highest-PSI pod on nodes
+// that stay under critical pressure after being tainted. Only pods in
+// Namespaces (if set) that match PodSelector (if set) are candidates.
+type Eviction struct {
+	PodSelector string   `yaml:"podSelector"`
+	Namespaces  []string `yaml:"namespaces"`
+	// CriticalFullAvg10 is the full.avg10 pressure, for any resource, above
+	// which eviction is considered.
+	CriticalFullAvg10 float64 `yaml:"criticalFullAvg10"`
+	// TaintedFor is how long a node must have been tainted before eviction.
+	TaintedFor time.Duration `yaml:"taintedFor"`
+	// NodeInterval and ClusterInterval are the minimum times between two
+	// evictions on the same node and across the cluster.
+	NodeInterval    time.Duration `yaml:"nodeInterval"`
+	ClusterInterval time.Duration `yaml:"clusterInterval"`
+	Enabled         bool          `yaml:"enabled"`
+}
+
+// Methods for computing the outlier bound of a node pool.
+const (
+	// OutlierMAD flags values above median + factor * median absolute deviation.
+	OutlierMAD = "mad"
+	// OutlierPercentile flags values above factor * the given percentile.
+	OutlierPercentile = "percentile"
+)
+
+// Outliers configures relative detection of nodes whose pressure stands
+// out from the rest of their pool. Nodes sharing the value of PoolLabel
+// form a pool (all nodes when empty). The computed bound is clamped to
+// [Floor, Ceiling], so nodes below Floor are never outliers and nodes
+// above Ceiling always are. Floor defaults to 10 and must be above 0.
+type Outliers struct {
+	PoolLabel string `yaml:"poolLabel"`
+	Method    string `yaml:"method"`
+	// Metrics are the dotted PSI values compared, e.g. "cpu.some.avg10".
+	Metrics    []string `yaml:"metrics"`
+	Factor     float64  `yaml:"factor"`
+	Percentile float64  `yaml:"percentile"`
+	Floor      float64  `yaml:"floor"`
+	Ceiling    float64  `yaml:"ceiling"`
+	// MinNodes is the smallest pool for which statistics are computed.
+	MinNodes int  `yaml:"minNodes"`
+	Enabled  bool `yaml:"enabled"`
+}
+
+// Policies for nodes that are NotReady, cordoned or being deleted.
+const (
+	// LifecycleSkip leaves the node alone: no PSI fetch and no taint changes.
+	LifecycleSkip = "skip"
+	// LifecycleUntaint removes the controller's taint and then skips the node.
+	LifecycleUntaint = "untaint"
+	// LifecycleEvaluate treats the node like any other.
+	LifecycleEvaluate = "evaluate"
+)
+
+// NodeLifecycle selects how nodes in special lifecycle states are handled.
+// When a node is in several states, Deleting takes precedence over
+// NotReady, which takes precedence over Unschedulable.
+type NodeLifecycle struct {
+	NotReady      string `yaml:"notReady"`
+	Unschedulable string `yaml:"unschedulable"`
+	Deleting      string `yaml:"deleting"`
+}
+
+// Actuators that signal pressure on a node.
+const (
+	// ActuatorTaint applies TaintKey with TaintEffect.
+	ActuatorTaint = "taint"
+	// ActuatorCondition sets the NodeCondition.Type condition in the node status.
+	ActuatorCondition = "condition"
+	// ActuatorLabel sets the NodeLabel.Key label to the dominant pressure
+	// resource (cpu, memory or io), or "none" once pressure subsides.
+	ActuatorLabel = "label"
+	// ActuatorAnnotation records when and why the node came under pressure
+	// in the NodeAnnotation.Key annotation.
+	ActuatorAnnotation = "annotation"
+	// ActuatorWebhook posts pressure and recovery events to Webhook.URL.
+	ActuatorWebhook = "webhook"
+)
+
+// NodeCondition configures the node condition set by the condition actuator.
+type NodeCondition struct {
+	Type string `yaml:"type"`
+}
+
+// NodeLabel configures the node label set by the label actuator.
+type NodeLabel struct {
+	Key string `yaml:"key"`
+}
+
+// NodeAnnotation configures the node annotation set by the annotation
+// actuator.
+type NodeAnnotation struct {
+	Key string `yaml:"key"`
+}
+
+// Webhook configures the endpoint notified by the webhook actuator.
+// HeadersFile holds extra request headers, one "Name: value" per line,
+// and is typically mounted from a Secret. BodyTemplate is a Go template
+// rendered with the batch of events; the batch is sent as JSON when it is
+// empty. Failed deliveries are attempted up to MaxAttempts times with a
+// delay starting at RetryBackoff and doubling each time. Events that
+// occur within BatchWindow of the first one are sent together, up to
+// MaxBatchSize.
+type Webhook struct {
+	URL          string        `yaml:"url"`
+	HeadersFile  string        `yaml:"headersFile"`
+	BodyTemplate string        `yaml:"bodyTemplate"`
+	Timeout      time.Duration `yaml:"timeout"`
+	MaxAttempts  int           `yaml:"maxAttempts"`
+	RetryBackoff time.Duration `yaml:"retryBackoff"`
+	BatchWindow  time.Duration `yaml:"batchWindow"`
+	MaxBatchSize int           `yaml:"maxBatchSize"`
+}
+
+// CloudEvents modes.
+const (
+	CloudEventsBinary     = "binary"
+	CloudEventsStructured = "structured"
+)
+
+// CloudEvents configures the CloudEvents emitted for controller decisions:
+// node.pressure.detected, node.tainted, node.untainted and poll.failed.
+// Mode selects the HTTP binary or structured content mode, and Source is
+// the source attribute of every event. Deliveries are retried like
+// webhook deliveries.
+type CloudEvents struct {
+	URL          string        `yaml:"url"`
+	Mode         string        `yaml:"mode"`
+	Source       string        `yaml:"source"`
+	HeadersFile  string        `yaml:"headersFile"`
+	Timeout      time.Duration `yaml:"timeout"`
+	MaxAttempts  int           `yaml:"maxAttempts"`
+	RetryBackoff time.Duration `yaml:"retryBackoff"`
+	Enabled      bool          `yaml:"enabled"`
+}
+
+// Profile selects the actuators for the nodes matching NodeSelector. The
+// first matching profile wins; nodes matching none use Config.Actuators.
+type Profile struct {
+	Name         string   `yaml:"name"`
+	NodeSelector string   `yaml:"nodeSelector"`
+	Actuators    []string `yaml:"actuators"`
+}
+
+// Autoscaler configures integration with cluster-autoscaler and Karpenter.
+type Autoscaler struct {
+	// PoolLabel groups nodes for the tainted capacity metrics, e.g.
+	// "karpenter.sh/nodepool"; all nodes form one pool when empty.
+	PoolLabel string `yaml:"poolLabel"`
+	// DisableScaleDown annotates tainted nodes with
+	// cluster-autoscaler.kubernetes.io/scale-down-disabled=true so that
+	// they are not removed for looking underutilised.
+	DisableScaleDown bool `yaml:"disableScaleDown"`
+}
+
+// Smoothing configures an exponential moving average applied to each
+// resource's PSI values before they are compared with thresholds. Each
+// field is the half-life of the average for that resource; 0 disables
+// smoothing for it.
+type Smoothing struct {
+	CPU    time.Duration `yaml:"cpu"`
+	Memory time.Duration `yaml:"memory"`
+	IO     time.Duration `yaml:"io"`
+}
+
+// CooldownBackoff lengthens the cooldown of nodes that are tainted
+// repeatedly. Each taint cycle multiplies the cooldown by Multiplier, up
+// to MaxCooldown; every QuietPeriod without a new taint forgets one cycle.
+type CooldownBackoff struct {
+	Multiplier  float64       `yaml:"multiplier"`
+	MaxCooldown time.Duration `yaml:"maxCooldown"`
+	QuietPeriod time.Duration `yaml:"quietPeriod"`
+	Enabled     bool          `yaml:"enabled"`
 }
 
 // LeaderElection holds leader election configuration.
@@ -41,17 +343,69 @@ type LeaderElection struct {
 	Enabled        bool          `yaml:"enabled"`
 }
 
+// PauseKey is the ConfigMap key of the pause switch.
+const PauseKey = "paused"
+
+// Pause configures the cluster-wide pause switch: while the PauseKey of
+// the ConfigMap is "true", the controller keeps evaluating nodes but makes
+// no changes to them.
+type Pause struct {
+	ConfigMap string `yaml:"configMap"`
+	Namespace string `yaml:"namespace"`
+	Enabled   bool   `yaml:"enabled"`
+}
+
+// DebugServer serves the controller's latest evaluation of each node as
+// JSON under /debug/nodes. The endpoints are not authenticated, so Address
+// defaults to the loopback interface.
+type DebugServer struct {
+	Address string `yaml:"address"`
+	Enabled bool   `yaml:"enabled"`
+}
+
+// Logging selects the log level (debug, info, warn or error) and format
+// (text or json). The --log-level and --log-format flags override them.
+type Logging struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
 // Config holds the application configuration.
 type Config struct {
-	TaintKey       string         `yaml:"taintKey"`
-	TaintEffect    string         `yaml:"taintEffect"`
+	TaintKey    string `yaml:"taintKey"`
+	TaintEffect string `yaml:"taintEffect"`
+	// Actuators lists how pressure is signalled on a node, in order;
+	// Profiles override it for groups of nodes.
+	Actuators      []string       `yaml:"actuators"`
+	NodeCondition  NodeCondition  `yaml:"nodeCondition"`
+	NodeLabel      NodeLabel      `yaml:"nodeLabel"`
+	NodeAnnotation NodeAnnotation `yaml:"nodeAnnotation"`
+	Webhook        Webhook        `yaml:"webhook"`
+	Profiles       []Profile      `yaml:"profiles"`
+	CloudEvents    CloudEvents    `yaml:"cloudEvents"`
 	KubeconfigPath string         `yaml:"kubeconfigPath"`
 	ConfigFilePath string         `yaml:"-"`
 	NodeFilter     string         `yaml:"nodeFilter"`
+	NodeLifecycle  NodeLifecycle  `yaml:"nodeLifecycle"`
 	LeaderElection LeaderElection `yaml:"leaderElection"`
 	PollInterval   time.Duration  `yaml:"pollInterval"`
 	CooldownPeriod time.Duration  `yaml:"cooldownPeriod"`
-	Thresholds     PSIThresholds  `yaml:"thresholds"`
+	// CooldownBackoff optionally extends CooldownPeriod for flapping nodes.
+	CooldownBackoff CooldownBackoff `yaml:"cooldownBackoff"`
+	Thresholds      PSIThresholds   `yaml:"thresholds"`
+	Smoothing       Smoothing       `yaml:"smoothing"`
+	MissingData     MissingData     `yaml:"missingData"`
+	Sources         PSISources      `yaml:"sources"`
+	PodAnalysis     PodAnalysis     `yaml:"podAnalysis"`
+	Eviction        Eviction        `yaml:"eviction"`
+	Outliers        Outliers        `yaml:"outliers"`
+	Autoscaler      Autoscaler      `yaml:"autoscaler"`
+	Logging         Logging         `yaml:"logging"`
+	DebugServer     DebugServer     `yaml:"debugServer"`
+	Pause           Pause           `yaml:"pause"`
+	// Schedules override thresholds during recurring time windows; the
+	// first active schedule wins.
+	Schedules []Schedule `yaml:"schedules"`
 }
 
 // LoadConfig reads the YAML configuration file and returns a Config struct.
@@ -81,20 +435,62 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, err
 	}
 
-	var cfg Config
-	err = yaml.Unmarshal(data, &cfg)
+	cfg, err := Parse(data)
 	if err != nil {
 		return nil, err
 	}
-
-	cfg.setDefaults()
 	cfg.ConfigFilePath = absPath
+	return cfg, nil
+}
 
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+// Parse decodes a configuration, applies the defaults and validates it.
+// Unknown keys are rejected, so that a typo does not silently leave a
+// setting at its default; decoding errors name the offending line.
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	if err := decodeStrict(data, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg.complete()
+}
+
+// ParseHelmValues parses the configuration under the config key of the
+// chart's values, e.g. charts/kube-dethrottler/values.yaml. The other
+// values are ignored.
+func ParseHelmValues(data []byte) (*Config, error) {
+	var values struct {
+		Config Config         `yaml:"config"`
+		Other  map[string]any `yaml:",inline"`
+	}
+	if err := decodeStrict(data, &values); err != nil {
+		return nil, err
+	}
+	return values.Config.complete()
+}
+
+func decodeStrict(data []byte, v any) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse configuration: %w", err)
 	}
+	// A second document would otherwise be ignored silently.
+	var extra yaml.Node
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return fmt.Errorf("failed to parse configuration: %w", err)
+		}
+		return fmt.Errorf("failed to parse configuration: line %d: only one YAML document is allowed", extra.Line)
+	}
+	return nil
+}
 
-	return &cfg, nil
+func (c *Config) complete() (*Config, error) {
+	c.setDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return c, nil
 }
 
 func (c *Config) setDefaults() {
@@ -104,12 +500,115 @@ func (c *Config) setDefaults() {
 	if c.CooldownPeriod == 0 {
 		c.CooldownPeriod = 5 * time.Minute
 	}
+	if c.CooldownBackoff.Multiplier == 0 {
+		c.CooldownBackoff.Multiplier = 2
+	}
+	if c.CooldownBackoff.MaxCooldown == 0 {
+		c.CooldownBackoff.MaxCooldown = time.Hour
+	}
+	if c.CooldownBackoff.QuietPeriod == 0 {
+		c.CooldownBackoff.QuietPeriod = time.Hour
+	}
 	if c.TaintKey == "" {
 		c.TaintKey = "kube-dethrottler/high-load"
 	}
 	if c.TaintEffect == "" {
 		c.TaintEffect = "NoSchedule"
 	}
+	for _, policy := range []*string{&c.NodeLifecycle.NotReady, &c.NodeLifecycle.Unschedulable, &c.NodeLifecycle.Deleting} {
+		if *policy == "" {
+			*policy = LifecycleSkip
+		}
+	}
+	if len(c.Actuators) == 0 {
+		c.Actuators = []string{ActuatorTaint}
+	}
+	if c.NodeCondition.Type == "" {
+		c.NodeCondition.Type = "PSIPressure"
+	}
+	if c.NodeLabel.Key == "" {
+		c.NodeLabel.Key = "kube-dethrottler.io/pressure"
+	}
+	if c.NodeAnnotation.Key == "" {
+		c.NodeAnnotation.Key = "kube-dethrottler.io/pressure"
+	}
+	if c.Webhook.Timeout == 0 {
+		c.Webhook.Timeout = 10 * time.Second
+	}
+	if c.Webhook.MaxAttempts == 0 {
+		c.Webhook.MaxAttempts = 4
+	}
+	if c.Webhook.RetryBackoff == 0 {
+		c.Webhook.RetryBackoff = time.Second
+	}
+	if c.Webhook.MaxBatchSize == 0 {
+		c.Webhook.MaxBatchSize = 50
+	}
+	if c.CloudEvents.Mode == "" {
+		c.CloudEvents.Mode = CloudEventsBinary
+	}
+	if c.CloudEvents.Source == "" {
+		c.CloudEvents.Source = "kube-dethrottler"
+	}
+	if c.CloudEvents.Timeout == 0 {
+		c.CloudEvents.Timeout = 10 * time.Second
+	}
+	if c.CloudEvents.MaxAttempts == 0 {
+		c.CloudEvents.MaxAttempts = 4
+	}
+	if c.CloudEvents.RetryBackoff == 0 {
+		c.CloudEvents.RetryBackoff = time.Second
+	}
+	if c.MissingData.Policy == "" {
+		c.MissingData.Policy = MissingDataIgnore
+	}
+	if len(c.Sources.Order) == 0 {
+		c.Sources.Order = []string{SourceSummary}
+	}
+	if c.Sources.Prometheus.NodeLabel == "" {
+		c.Sources.Prometheus.NodeLabel = "node"
+	}
+	if c.Sources.Prometheus.Timeout == 0 {
+		c.Sources.Prometheus.Timeout = 10 * time.Second
+	}
+	if c.PodAnalysis.TopN == 0 {
+		c.PodAnalysis.TopN = 3
+	}
+	if c.PodAnalysis.AnnotationKey == "" {
+		c.PodAnalysis.AnnotationKey = "kube-dethrottler.io/top-pods"
+	}
+	for i := range c.Thresholds.Trends.Rise {
+		if c.Thresholds.Trends.Rise[i].Polls == 0 {
+			c.Thresholds.Trends.Rise[i].Polls = 3
+		}
+	}
+	if c.Eviction.TaintedFor == 0 {
+		c.Eviction.TaintedFor = 10 * time.Minute
+	}
+	if c.Eviction.NodeInterval == 0 {
+		c.Eviction.NodeInterval = 10 * time.Minute
+	}
+	if c.Eviction.ClusterInterval == 0 {
+		c.Eviction.ClusterInterval = time.Minute
+	}
+	if c.Outliers.Method == "" {
+		c.Outliers.Method = OutlierMAD
+	}
+	if c.Outliers.Factor == 0 {
+		c.Outliers.Factor = 3
+	}
+	if c.Outliers.Percentile == 0 {
+		c.Outliers.Percentile = 90
+	}
+	if c.Outliers.Floor == 0 {
+		c.Outliers.Floor = 10
+	}
+	if c.Outliers.Ceiling == 0 {
+		c.Outliers.Ceiling = 100
+	}
+	if c.Outliers.MinNodes == 0 {
+		c.Outliers.MinNodes = 3
+	}
 	if c.LeaderElection.LeaseName == "" {
 		c.LeaderElection.LeaseName = "kube-dethrottler-leader"
 	}
@@ -128,6 +627,24 @@ func (c *Config) setDefaults() {
 	if c.LeaderElection.RetryPeriod == 0 {
 		c.LeaderElection.RetryPeriod = 2 * time.Second
 	}
+	if c.Logging.Level == "" {
+		c.Logging.Level = "info"
+	}
+	if c.Logging.Format == "" {
+		c.Logging.Format = logging.FormatText
+	}
+	if c.DebugServer.Address == "" {
+		c.DebugServer.Address = "127.0.0.1:8081"
+	}
+	if c.Pause.ConfigMap == "" {
+		c.Pause.ConfigMap = "kube-dethrottler-pause"
+	}
+	if c.Pause.Namespace == "" {
+		c.Pause.Namespace = os.Getenv("POD_NAMESPACE")
+		if c.Pause.Namespace == "" {
+			c.Pause.Namespace = "kube-system"
+		}
+	}
 }
 
 // Validate checks if the configuration is valid.
@@ -143,6 +660,18 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("cooldownPeriod (%s) must be greater than pollInterval (%s)", c.CooldownPeriod, c.PollInterval)
 	}
 
+	if b := c.CooldownBackoff; b.Enabled {
+		if b.Multiplier <= 1 {
+			return fmt.Errorf("cooldownBackoff.multiplier must be greater than 1, got %.2f", b.Multiplier)
+		}
+		if b.MaxCooldown < c.CooldownPeriod {
+			return fmt.Errorf("cooldownBackoff.maxCooldown (%s) must not be shorter than cooldownPeriod (%s)", b.MaxCooldown, c.CooldownPeriod)
+		}
+		if b.QuietPeriod <= 0 {
+			return fmt.Errorf("cooldownBackoff.quietPeriod must be positive, got %s", b.QuietPeriod)
+		}
+	}
+
 	validEffects := map[string]bool{
 		"NoSchedule":       true,
 		"PreferNoSchedule": true,
@@ -152,6 +681,61 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid taintEffect: %s. Must be one of: NoSchedule, PreferNoSchedule, NoExecute", c.TaintEffect)
 	}
 
+	if err := c.validateActuators(); err != nil {
+		return err
+	}
+
+	if err := c.CloudEvents.validate(); err != nil {
+		return err
+	}
+
+	if _, err := logging.New(io.Discard, c.Logging.Level, c.Logging.Format); err != nil {
+		return fmt.Errorf("logging: %w", err)
+	}
+
+	if c.Pause.Enabled && (c.Pause.ConfigMap == "" || c.Pause.Namespace == "") {
+		return fmt.Errorf("pause.configMap and pause.namespace are required when the pause switch is enabled")
+	}
+
+	if c.DebugServer.Enabled {
+		if _, _, err := net.SplitHostPort(c.DebugServer.Address); err != nil {
+			return fmt.Errorf("invalid debugServer.address: %w", err)
+		}
+	}
+
+	for name, policy := range map[string]string{
+		"notReady":      c.NodeLifecycle.NotReady,
+		"unschedulable": c.NodeLifecycle.Unschedulable,
+		"deleting":      c.NodeLifecycle.Deleting,
+	} {
+		switch policy {
+		case "", LifecycleSkip, LifecycleUntaint, LifecycleEvaluate:
+		default:
+			return fmt.Errorf("invalid nodeLifecycle.%s: %s. Must be one of: skip, untaint, evaluate", name, policy)
+		}
+	}
+
+	switch c.MissingData.Policy {
+	case "", MissingDataIgnore, MissingDataBreach, MissingDataUnknown:
+	default:
+		return fmt.Errorf("invalid missingData.policy: %s. Must be one of: ignore, breach, unknown", c.MissingData.Policy)
+	}
+	if c.MissingData.MaxSampleAge < 0 {
+		return fmt.Errorf("missingData.maxSampleAge must not be negative, got %s", c.MissingData.MaxSampleAge)
+	}
+
+	if err := c.Sources.validate(); err != nil {
+		return err
+	}
+
+	if c.PodAnalysis.TopN < 0 {
+		return fmt.Errorf("podAnalysis.topN must not be negative, got %d", c.PodAnalysis.TopN)
+	}
+
+	if err := c.Eviction.validate(); err != nil {
+		return err
+	}
+
 	if err := validatePSIAverages(c.Thresholds.CPU.Some, "cpu.some"); err != nil {
 		return err
 	}
@@ -171,10 +755,294 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.Thresholds.Signals.validate(); err != nil {
+		return err
+	}
+
+	if c.Smoothing.CPU < 0 || c.Smoothing.Memory < 0 || c.Smoothing.IO < 0 {
+		return fmt.Errorf("smoothing half-lives must not be negative")
+	}
+
+	if err := validateSchedules(c.Schedules); err != nil {
+		return err
+	}
+
+	if err := c.Outliers.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Thresholds.Trends.validate(); err != nil {
+		return err
+	}
+
+	if err := validateRules(c.Thresholds.Rules); err != nil {
+		return err
+	}
+
 	if !c.hasAnyThreshold() {
-		return fmt.Errorf("at least one PSI threshold must be set (non-zero)")
+		return fmt.Errorf("at least one PSI threshold, signal, trend, rule or outlier detection must be set (non-zero)")
+	}
+
+	return nil
+}
+
+func (c *Config) validateActuators() error {
+	if err := c.validateActuatorList("actuators", c.Actuators); err != nil {
+		return err
+	}
+	names := make(map[string]bool, len(c.Profiles))
+	for i, p := range c.Profiles {
+		if p.Name == "" {
+			return fmt.Errorf("profiles[%d]: name must be set", i)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("profiles: duplicate profile %s", p.Name)
+		}
+		names[p.Name] = true
+		if _, err := labels.Parse(p.NodeSelector); err != nil {
+			return fmt.Errorf("invalid profiles[%s].nodeSelector: %w", p.Name, err)
+		}
+		if len(p.Actuators) == 0 {
+			return fmt.Errorf("profiles[%s].actuators must not be empty", p.Name)
+		}
+		if err := c.validateActuatorList("profiles["+p.Name+"].actuators", p.Actuators); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateActuatorList(field string, actuators []string) error {
+	seen := make(map[string]bool, len(actuators))
+	for _, a := range actuators {
+		switch a {
+		case ActuatorTaint:
+		case ActuatorCondition:
+			if c.NodeCondition.Type == "" {
+				return fmt.Errorf("nodeCondition.type must be set when the condition actuator is used")
+			}
+		case ActuatorLabel:
+			if c.NodeLabel.Key == "" {
+				return fmt.Errorf("nodeLabel.key must be set when the label actuator is used")
+			}
+		case ActuatorAnnotation:
+			if c.NodeAnnotation.Key == "" {
+				return fmt.Errorf("nodeAnnotation.key must be set when the annotation actuator is used")
+			}
+		case ActuatorWebhook:
+			if err := c.Webhook.validate(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("invalid actuator: %s. Must be one of: taint, condition, label, annotation, webhook", a)
+		}
+		if seen[a] {
+			return fmt.Errorf("%s: duplicate actuator %s", field, a)
+		}
+		seen[a] = true
+	}
+	return nil
+}
+
+func (w *Webhook) validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook.url must be an http(s) URL when the webhook actuator is used")
+	}
+	if w.MaxAttempts < 0 || w.MaxBatchSize < 0 {
+		return fmt.Errorf("webhook.maxAttempts and webhook.maxBatchSize must not be negative")
+	}
+	if w.Timeout < 0 || w.RetryBackoff < 0 || w.BatchWindow < 0 {
+		return fmt.Errorf("webhook durations must not be negative")
+	}
+	if w.BodyTemplate != "" {
+		if err := validateBodyTemplate(w.BodyTemplate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBodyTemplate parses a webhook body template. The functions must
+// match those notify.ParseTemplate provides; only their names matter here.
+func validateBodyTemplate(text string) error {
+	_, err := template.New("body").Funcs(template.FuncMap{
+		"json": func(any) (string, error) { return "", nil },
+	}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid webhook body template: %w", err)
 	}
+	return nil
+}
+
+func (e *CloudEvents) validate() error {
+	if !e.Enabled {
+		return nil
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("cloudEvents.url must be an http(s) URL when cloudEvents is enabled")
+	}
+	if e.Mode != CloudEventsBinary && e.Mode != CloudEventsStructured {
+		return fmt.Errorf("invalid cloudEvents.mode: %s. Must be one of: binary, structured", e.Mode)
+	}
+	if e.Source == "" {
+		return fmt.Errorf("cloudEvents.source must be set when cloudEvents is enabled")
+	}
+	if e.MaxAttempts < 0 || e.Timeout < 0 || e.RetryBackoff < 0 {
+		return fmt.Errorf("cloudEvents.maxAttempts and durations must not be negative")
+	}
+	return nil
+}
 
+func (s *PSISources) validate() error {
+	seen := make(map[string]bool, len(s.Order))
+	for i, name := range s.Order {
+		switch name {
+		case SourceSummary:
+		case SourcePrometheus:
+			if s.Prometheus.URL == "" {
+				return fmt.Errorf("sources.prometheus.url must be set when the prometheus source is used")
+			}
+		case SourceLastKnown:
+			if i != len(s.Order)-1 {
+				return fmt.Errorf("sources.order: %s must be the last source", SourceLastKnown)
+			}
+			if s.LastKnownMaxAge <= 0 {
+				return fmt.Errorf("sources.lastKnownMaxAge must be positive when the %s source is used", SourceLastKnown)
+			}
+		default:
+			return fmt.Errorf("invalid source in sources.order: %s. Must be one of: summary, prometheus, lastKnown", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("sources.order: duplicate source %s", name)
+		}
+		seen[name] = true
+	}
+	if len(s.Order) == 1 && s.Order[0] == SourceLastKnown {
+		return fmt.Errorf("sources.order: %s requires at least one other source", SourceLastKnown)
+	}
+	return nil
+}
+
+func (e *Eviction) validate() error {
+	if !e.Enabled {
+		return nil
+	}
+	if len(e.Namespaces) == 0 && e.PodSelector == "" {
+		return fmt.Errorf("eviction requires namespaces or podSelector to restrict candidate pods")
+	}
+	if _, err := labels.Parse(e.PodSelector); err != nil {
+		return fmt.Errorf("invalid eviction.podSelector: %w", err)
+	}
+	if e.CriticalFullAvg10 <= 0 || e.CriticalFullAvg10 > 100 {
+		return fmt.Errorf("eviction.criticalFullAvg10 must be between 0 and 100 (exclusive of 0), got %.2f", e.CriticalFullAvg10)
+	}
+	if e.TaintedFor < 0 || e.NodeInterval < 0 || e.ClusterInterval < 0 {
+		return fmt.Errorf("eviction durations must not be negative")
+	}
+	return nil
+}
+
+func (s *ExtraSignals) validate() error {
+	memBytes, err := s.MemoryAvailableBelowBytes()
+	if err != nil {
+		return fmt.Errorf("invalid signals.memoryAvailableBelow: %w", err)
+	}
+	if memBytes < 0 {
+		return fmt.Errorf("signals.memoryAvailableBelow must not be negative, got %s", s.MemoryAvailableBelow)
+	}
+	if s.FSAvailableBelowPercent < 0 || s.FSAvailableBelowPercent > 100 {
+		return fmt.Errorf("signals.fsAvailableBelowPercent must be between 0 and 100, got %.2f", s.FSAvailableBelowPercent)
+	}
+	for _, cond := range append(append([]string{}, s.TaintOnConditions...), s.HoldOnConditions...) {
+		if cond == "" {
+			return fmt.Errorf("signals condition types must not be empty")
+		}
+	}
+	return nil
+}
+
+func (t *TrendTriggers) validate() error {
+	for _, r := range t.Rise {
+		if !psi.ValidMetric(r.Metric) {
+			return fmt.Errorf("invalid trends.rise metric: %q. Must be <cpu|memory|io>.<some|full>.<avg10|avg60|avg300>", r.Metric)
+		}
+		if r.RiseBy <= 0 || r.RiseBy > 100 {
+			return fmt.Errorf("trends.rise %s: riseBy must be between 0 and 100 (exclusive of 0), got %.2f", r.Metric, r.RiseBy)
+		}
+		if r.Polls < 2 {
+			return fmt.Errorf("trends.rise %s: polls must be at least 2, got %d", r.Metric, r.Polls)
+		}
+	}
+	for _, d := range t.Divergence {
+		if !psi.ValidMetric(d.Pressure + ".avg10") {
+			return fmt.Errorf("invalid trends.divergence pressure: %q. Must be <cpu|memory|io>.<some|full>", d.Pressure)
+		}
+		if d.Factor <= 1 {
+			return fmt.Errorf("trends.divergence %s: factor must be greater than 1, got %.2f", d.Pressure, d.Factor)
+		}
+		if d.MinAvg10 < 0 || d.MinAvg10 > 100 {
+			return fmt.Errorf("trends.divergence %s: minAvg10 must be between 0 and 100, got %.2f", d.Pressure, d.MinAvg10)
+		}
+	}
+	return nil
+}
+
+func (o *Outliers) validate() error {
+	if !o.Enabled {
+		return nil
+	}
+	switch o.Method {
+	case OutlierMAD, OutlierPercentile:
+	default:
+		return fmt.Errorf("invalid outliers.method: %s. Must be one of: mad, percentile", o.Method)
+	}
+	if len(o.Metrics) == 0 {
+		return fmt.Errorf("outliers.metrics must not be empty when outlier detection is enabled")
+	}
+	for _, m := range o.Metrics {
+		if !psi.ValidMetric(m) {
+			return fmt.Errorf("invalid outliers metric: %q. Must be <cpu|memory|io>.<some|full>.<avg10|avg60|avg300>", m)
+		}
+	}
+	if o.Factor <= 0 {
+		return fmt.Errorf("outliers.factor must be positive, got %.2f", o.Factor)
+	}
+	if o.Method == OutlierPercentile && (o.Percentile <= 0 || o.Percentile > 100) {
+		return fmt.Errorf("outliers.percentile must be between 0 and 100 (exclusive of 0), got %.2f", o.Percentile)
+	}
+	if o.Floor < 0 || o.Ceiling < 0 || o.Floor > 100 || o.Ceiling > 100 {
+		return fmt.Errorf("outliers.floor and outliers.ceiling must be between 0 and 100")
+	}
+	// On an idle pool the median and the deviation are both 0, so without
+	// a floor any pressure at all would be an outlier.
+	if o.Floor == 0 {
+		return fmt.Errorf("outliers.floor must be above 0")
+	}
+	if o.Ceiling < o.Floor {
+		return fmt.Errorf("outliers.ceiling (%.2f) must not be lower than outliers.floor (%.2f)", o.Ceiling, o.Floor)
+	}
+	if o.MinNodes < 2 {
+		return fmt.Errorf("outliers.minNodes must be at least 2, got %d", o.MinNodes)
+	}
+	return nil
+}
+
+func validateRules(rs []Rule) error {
+	seen := make(map[string]bool, len(rs))
+	for _, r := range rs {
+		if r.Name == "" {
+			return fmt.Errorf("rules: name must not be empty")
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("rules: duplicate rule name %s", r.Name)
+		}
+		seen[r.Name] = true
+		if _, err := rules.Compile(r.Name, r.Expression); err != nil {
+			return fmt.Errorf("invalid rules: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -192,6 +1060,19 @@ func validatePSIAverages(a PSIAverages, prefix string) error {
 }
 
 func (c *Config) hasAnyThreshold() bool {
+	if c.Outliers.Enabled {
+		return true
+	}
+	for i := range c.Schedules {
+		if c.Schedules[i].hasAnyThreshold() {
+			return true
+		}
+	}
+	signals := c.Thresholds.Signals
+	if signals.MemoryAvailableBelow != "" || signals.FSAvailableBelowPercent > 0 || len(signals.TaintOnConditions) > 0 ||
+		len(c.Thresholds.Rules) > 0 || len(c.Thresholds.Trends.Rise) > 0 || len(c.Thresholds.Trends.Divergence) > 0 {
+		return true
+	}
 	return hasAnyAvg(c.Thresholds.CPU.Some) ||
 		hasAnyAvg(c.Thresholds.CPU.Full) ||
 		hasAnyAvg(c.Thresholds.Memory.Some) ||
@@ -200,6 +1081,11 @@ func (c *Config) hasAnyThreshold() bool {
 		hasAnyAvg(c.Thresholds.IO.Full)
 }
 
+// Enabled reports whether any threshold is set for the resource.
+func (p PSIPressure) Enabled() bool {
+	return hasAnyAvg(p.Some) || hasAnyAvg(p.Full)
+}
+
 func hasAnyAvg(a PSIAverages) bool {
 	return a.Avg10 > 0 || a.Avg60 > 0 || a.Avg300 > 0
 }
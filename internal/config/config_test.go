@@ -41,6 +41,18 @@ func TestLoadConfig_Defaults(t *testing.T) {
 	if cfg.LeaderElection.LeaseName != "kube-dethrottler-leader" {
 		t.Errorf("cfg.LeaderElection.LeaseName = %v, want %v", cfg.LeaderElection.LeaseName, "kube-dethrottler-leader")
 	}
+	if cfg.MissingData.Policy != MissingDataIgnore {
+		t.Errorf("cfg.MissingData.Policy = %v, want %v", cfg.MissingData.Policy, MissingDataIgnore)
+	}
+	if cfg.Logging.Level != "info" || cfg.Logging.Format != "text" {
+		t.Errorf("cfg.Logging = %+v, want info/text", cfg.Logging)
+	}
+	if cfg.DebugServer.Address != "127.0.0.1:8081" {
+		t.Errorf("cfg.DebugServer.Address = %v, want %v", cfg.DebugServer.Address, "127.0.0.1:8081")
+	}
+	if cfg.Outliers.Floor != 10 {
+		t.Errorf("cfg.Outliers.Floor = %v, want %v", cfg.Outliers.Floor, 10)
+	}
 }
 
 func TestLoadConfig_CustomValues(t *testing.T) {
@@ -196,6 +208,254 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "cooldownPeriod",
 		},
+		{
+			name: "cooldown backoff max below base cooldown",
+			config: Config{
+				PollInterval:    30 * time.Second,
+				CooldownPeriod:  5 * time.Minute,
+				CooldownBackoff: CooldownBackoff{Enabled: true, Multiplier: 2, MaxCooldown: time.Minute, QuietPeriod: time.Hour},
+				TaintEffect:     "NoSchedule",
+				Thresholds:      PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+			},
+			wantErr: true,
+			errMsg:  "cooldownBackoff.maxCooldown",
+		},
+		{
+			name: "cooldown backoff multiplier not above 1",
+			config: Config{
+				PollInterval:    30 * time.Second,
+				CooldownPeriod:  5 * time.Minute,
+				CooldownBackoff: CooldownBackoff{Enabled: true, Multiplier: 1, MaxCooldown: time.Hour, QuietPeriod: time.Hour},
+				TaintEffect:     "NoSchedule",
+				Thresholds:      PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+			},
+			wantErr: true,
+			errMsg:  "cooldownBackoff.multiplier",
+		},
+		{
+			name: "negative smoothing half-life",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Smoothing:      Smoothing{Memory: -time.Minute},
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+			},
+			wantErr: true,
+			errMsg:  "smoothing half-lives must not be negative",
+		},
+		{
+			name: "invalid node lifecycle policy",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				NodeLifecycle:  NodeLifecycle{Unschedulable: "ignore"},
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid nodeLifecycle.unschedulable",
+		},
+		{
+			name: "unknown actuator",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Actuators:      []string{"taint", "page"},
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid actuator: page",
+		},
+		{
+			name: "condition actuator without type",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Actuators:      []string{"condition"},
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+			},
+			wantErr: true,
+			errMsg:  "nodeCondition.type",
+		},
+		{
+			name: "label actuator without key",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Actuators:      []string{"label"},
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+			},
+			wantErr: true,
+			errMsg:  "nodeLabel.key",
+		},
+		{
+			name: "webhook actuator without url",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				Actuators:      []string{"taint", "webhook"},
+			},
+			wantErr: true,
+			errMsg:  "webhook.url",
+		},
+		{
+			name: "webhook with json body template",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				Actuators:      []string{"webhook"},
+				Webhook:        Webhook{URL: "https://hooks.example.com/psi", BodyTemplate: `{"events": {{ json .Events }}}`},
+			},
+			wantErr: false,
+		},
+		{
+			name: "webhook with invalid body template",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				Actuators:      []string{"webhook"},
+				Webhook:        Webhook{URL: "https://hooks.example.com/psi", BodyTemplate: "{{ .Events"},
+			},
+			wantErr: true,
+			errMsg:  "invalid webhook body template",
+		},
+		{
+			name: "cloudEvents with invalid mode",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				CloudEvents:    CloudEvents{Enabled: true, URL: "http://broker.knative:80", Mode: "batch", Source: "kube-dethrottler"},
+			},
+			wantErr: true,
+			errMsg:  "invalid cloudEvents.mode: batch",
+		},
+		{
+			name: "cloudEvents without url",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				CloudEvents:    CloudEvents{Enabled: true, Mode: "binary", Source: "kube-dethrottler"},
+			},
+			wantErr: true,
+			errMsg:  "cloudEvents.url",
+		},
+		{
+			name: "invalid log level",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				Logging:        Logging{Level: "verbose"},
+			},
+			wantErr: true,
+			errMsg:  "invalid log level: verbose",
+		},
+		{
+			name: "invalid log format",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				Logging:        Logging{Format: "logfmt"},
+			},
+			wantErr: true,
+			errMsg:  "invalid log format: logfmt",
+		},
+		{
+			name: "debug server with invalid address",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				DebugServer:    DebugServer{Enabled: true, Address: "8081"},
+			},
+			wantErr: true,
+			errMsg:  "invalid debugServer.address",
+		},
+		{
+			name: "pause switch without configmap",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				Pause:          Pause{Enabled: true, Namespace: "kube-system"},
+			},
+			wantErr: true,
+			errMsg:  "pause.configMap",
+		},
+		{
+			name: "valid profiles",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				NodeLabel:      NodeLabel{Key: "kube-dethrottler.io/pressure"},
+				Webhook:        Webhook{URL: "https://hooks.example.com/psi"},
+				Profiles: []Profile{
+					{Name: "affinity", NodeSelector: "pool=batch", Actuators: []string{"label", "webhook"}},
+					{Name: "default", Actuators: []string{"taint"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "profile with invalid selector",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				Profiles:       []Profile{{Name: "bad", NodeSelector: "pool in (", Actuators: []string{"taint"}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid profiles[bad].nodeSelector",
+		},
+		{
+			name: "profile without actuators",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				Profiles:       []Profile{{Name: "empty", NodeSelector: "pool=a"}},
+			},
+			wantErr: true,
+			errMsg:  "profiles[empty].actuators must not be empty",
+		},
+		{
+			name: "duplicate profile",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 25}}},
+				Profiles: []Profile{
+					{Name: "a", Actuators: []string{"taint"}},
+					{Name: "a", Actuators: []string{"taint"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "duplicate profile a",
+		},
 		{
 			name: "invalid taint effect",
 			config: Config{
@@ -229,6 +489,298 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "must be between 0 and 100",
 		},
+		{
+			name: "invalid missing data policy",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 10.0}}},
+				MissingData:    MissingData{Policy: "panic"},
+			},
+			wantErr: true,
+			errMsg:  "invalid missingData.policy",
+		},
+		{
+			name: "negative max sample age",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 10.0}}},
+				MissingData:    MissingData{Policy: MissingDataBreach, MaxSampleAge: -time.Second},
+			},
+			wantErr: true,
+			errMsg:  "missingData.maxSampleAge",
+		},
+		{
+			name: "unknown psi source",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 10.0}}},
+				Sources:        PSISources{Order: []string{SourceSummary, "metrics-server"}},
+			},
+			wantErr: true,
+			errMsg:  "invalid source",
+		},
+		{
+			name: "prometheus source without url",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 10.0}}},
+				Sources:        PSISources{Order: []string{SourceSummary, SourcePrometheus}},
+			},
+			wantErr: true,
+			errMsg:  "sources.prometheus.url",
+		},
+		{
+			name: "last known source not last",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 10.0}}},
+				Sources:        PSISources{Order: []string{SourceLastKnown, SourceSummary}, LastKnownMaxAge: time.Minute},
+			},
+			wantErr: true,
+			errMsg:  "must be the last source",
+		},
+		{
+			name: "valid fallback chain",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 10.0}}},
+				Sources: PSISources{
+					Order:           []string{SourceSummary, SourcePrometheus, SourceLastKnown},
+					Prometheus:      PrometheusSource{URL: "http://prometheus:9090"},
+					LastKnownMaxAge: 2 * time.Minute,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "eviction without allowlist",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 10.0}}},
+				Eviction:       Eviction{Enabled: true, CriticalFullAvg10: 50},
+			},
+			wantErr: true,
+			errMsg:  "eviction requires namespaces or podSelector",
+		},
+		{
+			name: "eviction with invalid selector",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 10.0}}},
+				Eviction:       Eviction{Enabled: true, CriticalFullAvg10: 50, PodSelector: "app in (a"},
+			},
+			wantErr: true,
+			errMsg:  "invalid eviction.podSelector",
+		},
+		{
+			name: "eviction without critical level",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{CPU: PSIPressure{Some: PSIAverages{Avg10: 10.0}}},
+				Eviction:       Eviction{Enabled: true, Namespaces: []string{"batch"}},
+			},
+			wantErr: true,
+			errMsg:  "eviction.criticalFullAvg10",
+		},
+		{
+			name: "signals only",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{Signals: ExtraSignals{MemoryAvailableBelow: "1Gi"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid memory quantity",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{Signals: ExtraSignals{MemoryAvailableBelow: "lots"}},
+			},
+			wantErr: true,
+			errMsg:  "invalid signals.memoryAvailableBelow",
+		},
+		{
+			name: "fs percent above 100",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{Signals: ExtraSignals{FSAvailableBelowPercent: 120}},
+			},
+			wantErr: true,
+			errMsg:  "signals.fsAvailableBelowPercent",
+		},
+		{
+			name: "rules only",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds: PSIThresholds{Rules: []Rule{
+					{Name: "cpu-sustained", Expression: "cpu.some.avg10 > 40.0 && cpu.some.avg60 > 20.0"},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rule does not compile",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{Rules: []Rule{{Name: "broken", Expression: "cpu.some.avg10 +"}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid rules: rule broken",
+		},
+		{
+			name: "rule does not return bool",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{Rules: []Rule{{Name: "number", Expression: "cpu.some.avg10"}}},
+			},
+			wantErr: true,
+			errMsg:  "must evaluate to bool",
+		},
+		{
+			name: "duplicate rule names",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds: PSIThresholds{Rules: []Rule{
+					{Name: "r", Expression: "cpu.some.avg10 > 1.0"},
+					{Name: "r", Expression: "io.some.avg10 > 1.0"},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "duplicate rule name r",
+		},
+		{
+			name: "trends only",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds: PSIThresholds{Trends: TrendTriggers{
+					Rise:       []RiseTrigger{{Metric: "cpu.some.avg10", RiseBy: 15, Polls: 3}},
+					Divergence: []DivergenceTrigger{{Pressure: "memory.some", Factor: 3, MinAvg10: 10}},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rise trigger with invalid metric",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{Trends: TrendTriggers{Rise: []RiseTrigger{{Metric: "cpu.avg10", RiseBy: 15, Polls: 3}}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid trends.rise metric",
+		},
+		{
+			name: "rise trigger with one poll",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{Trends: TrendTriggers{Rise: []RiseTrigger{{Metric: "cpu.some.avg10", RiseBy: 15, Polls: 1}}}},
+			},
+			wantErr: true,
+			errMsg:  "polls must be at least 2",
+		},
+		{
+			name: "divergence factor not above 1",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Thresholds:     PSIThresholds{Trends: TrendTriggers{Divergence: []DivergenceTrigger{{Pressure: "io.full", Factor: 1}}}},
+			},
+			wantErr: true,
+			errMsg:  "factor must be greater than 1",
+		},
+		{
+			name: "outliers only",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Outliers: Outliers{
+					Enabled: true, Method: OutlierMAD, Metrics: []string{"cpu.some.avg10"},
+					Factor: 3, Floor: 10, Ceiling: 80, MinNodes: 3,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "outliers with invalid method",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Outliers: Outliers{
+					Enabled: true, Method: "zscore", Metrics: []string{"cpu.some.avg10"},
+					Factor: 3, Ceiling: 100, MinNodes: 3,
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid outliers.method",
+		},
+		{
+			name: "outliers floor zero",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Outliers: Outliers{
+					Enabled: true, Method: OutlierMAD, Metrics: []string{"cpu.some.avg10"},
+					Factor: 3, Ceiling: 100, MinNodes: 3,
+				},
+			},
+			wantErr: true,
+			errMsg:  "outliers.floor must be above 0",
+		},
+		{
+			name: "outliers ceiling below floor",
+			config: Config{
+				PollInterval:   30 * time.Second,
+				CooldownPeriod: 5 * time.Minute,
+				TaintEffect:    "NoSchedule",
+				Outliers: Outliers{
+					Enabled: true, Method: OutlierPercentile, Metrics: []string{"io.some.avg60"},
+					Factor: 1.5, Percentile: 90, Floor: 50, Ceiling: 20, MinNodes: 3,
+				},
+			},
+			wantErr: true,
+			errMsg:  "must not be lower than outliers.floor",
+		},
 		{
 			name: "all thresholds disabled",
 			config: Config{
@@ -238,7 +790,7 @@ func TestConfig_Validate(t *testing.T) {
 				Thresholds:     PSIThresholds{},
 			},
 			wantErr: true,
-			errMsg:  "at least one PSI threshold must be set",
+			errMsg:  "at least one PSI threshold, signal, trend, rule or outlier detection must be set",
 		},
 	}
 
@@ -257,3 +809,71 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_Strict(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		errMsg  string
+		wantErr bool
+	}{
+		{
+			name: "known keys",
+			data: "thresholds:\n  cpu:\n    some:\n      avg10: 25\n",
+		},
+		{
+			name:    "empty file",
+			data:    "",
+			wantErr: true,
+			errMsg:  "at least one PSI threshold",
+		},
+		{
+			name:    "unknown key",
+			data:    "thresholds:\n  cpu:\n    some:\n      avg_10: 25\n",
+			wantErr: true,
+			errMsg:  "line 4: field avg_10 not found",
+		},
+		{
+			name:    "wrong type",
+			data:    "pollInterval: 30s\ntaintKey: [a, b]\n",
+			wantErr: true,
+			errMsg:  "line 2:",
+		},
+		{
+			name:    "second document",
+			data:    "thresholds:\n  cpu:\n    some:\n      avg10: 25\n---\npollInterval: 1s\n",
+			wantErr: true,
+			errMsg:  "line 5: only one YAML document is allowed",
+		},
+		{
+			name: "trailing document separator",
+			data: "---\nthresholds:\n  cpu:\n    some:\n      avg10: 25\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestParseHelmValues(t *testing.T) {
+	cfg, err := ParseHelmValues([]byte("replicaCount: 2\nconfig:\n  taintEffect: NoExecute\n  thresholds: {cpu: {some: {avg10: 25}}}\n"))
+	if err != nil {
+		t.Fatalf("ParseHelmValues() error = %v", err)
+	}
+	if cfg.TaintEffect != "NoExecute" || cfg.PollInterval != 30*time.Second {
+		t.Errorf("ParseHelmValues() = %+v, want the config value with defaults", cfg)
+	}
+
+	_, err = ParseHelmValues([]byte("replicaCount: 2\nconfig:\n  taintEfect: NoExecute\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3: field taintEfect not found") {
+		t.Errorf("ParseHelmValues() error = %v, want the unknown config key", err)
+	}
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ThresholdOverrides replaces the PSI thresholds of the resources that are
+// set; resources left unset keep their base thresholds.
+type ThresholdOverrides struct {
+	CPU    *PSIPressure `yaml:"cpu"`
+	Memory *PSIPressure `yaml:"memory"`
+	IO     *PSIPressure `yaml:"io"`
+}
+
+// Schedule applies threshold overrides during a recurring weekly time
+// window. Days lists weekday abbreviations ("Mon" ... "Sun"), all days when
+// empty. Start and End are "HH:MM" in TimeZone (UTC when empty); a window
+// whose End is not after Start spans midnight, and an empty window covers
+// the whole day.
+type Schedule struct {
+	Name       string             `yaml:"name"`
+	TimeZone   string             `yaml:"timeZone"`
+	Days       []string           `yaml:"days"`
+	Start      string             `yaml:"start"`
+	End        string             `yaml:"end"`
+	Thresholds ThresholdOverrides `yaml:"thresholds"`
+	// loc is TimeZone resolved by Validate, so that polls do not load it.
+	loc *time.Location
+}
+
+// DefaultScheduleName is the name reported while no schedule is active; a
+// schedule must not use it.
+const DefaultScheduleName = "default"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Active reports whether t falls within the schedule's window.
+func (s *Schedule) Active(t time.Time) (bool, error) {
+	loc, err := s.location()
+	if err != nil {
+		return false, err
+	}
+	start, err := parseClock(s.Start)
+	if err != nil {
+		return false, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseClock(s.End)
+	if err != nil {
+		return false, fmt.Errorf("invalid end: %w", err)
+	}
+
+	t = t.In(loc)
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	day := t.Weekday()
+	if start >= end && now < end {
+		// Early-morning part of a window that started the previous day.
+		day = (day + 6) % 7
+	}
+	dayMatches, err := s.onDay(day)
+	if err != nil || !dayMatches {
+		return false, err
+	}
+
+	switch {
+	case start == end:
+		return true, nil
+	case start < end:
+		return now >= start && now < end, nil
+	default:
+		return now >= start || now < end, nil
+	}
+}
+
+// location returns the schedule's time zone, loading it unless Validate
+// already did.
+func (s *Schedule) location() (*time.Location, error) {
+	if s.loc != nil {
+		return s.loc, nil
+	}
+	if s.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeZone %q: %w", s.TimeZone, err)
+	}
+	return loc, nil
+}
+
+func (s *Schedule) onDay(day time.Weekday) (bool, error) {
+	if len(s.Days) == 0 {
+		return true, nil
+	}
+	for _, d := range s.Days {
+		wd, ok := weekdays[strings.ToLower(d)]
+		if !ok {
+			return false, fmt.Errorf("invalid day %q. Must be one of: Mon, Tue, Wed, Thu, Fri, Sat, Sun", d)
+		}
+		if wd == day {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasAnyThreshold reports whether the schedule enables any threshold.
+func (s *Schedule) hasAnyThreshold() bool {
+	for _, p := range []*PSIPressure{s.Thresholds.CPU, s.Thresholds.Memory, s.Thresholds.IO} {
+		if p != nil && p.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns base with the schedule's overrides applied.
+func (s *Schedule) Apply(base PSIThresholds) PSIThresholds {
+	if s.Thresholds.CPU != nil {
+		base.CPU = *s.Thresholds.CPU
+	}
+	if s.Thresholds.Memory != nil {
+		base.Memory = *s.Thresholds.Memory
+	}
+	if s.Thresholds.IO != nil {
+		base.IO = *s.Thresholds.IO
+	}
+	return base
+}
+
+// parseClock parses "HH:MM" into the duration since midnight; "" is midnight.
+func parseClock(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q must be in HH:MM format", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func validateSchedules(schedules []Schedule) error {
+	seen := make(map[string]bool, len(schedules))
+	for i := range schedules {
+		s := &schedules[i]
+		if s.Name == "" {
+			return fmt.Errorf("schedules: name must not be empty")
+		}
+		if s.Name == DefaultScheduleName {
+			return fmt.Errorf("schedules: the name %s is reserved for the base thresholds", DefaultScheduleName)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("schedules: duplicate schedule name %s", s.Name)
+		}
+		seen[s.Name] = true
+		loc, err := s.location()
+		if err != nil {
+			return fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+		s.loc = loc
+		// Evaluating once surfaces day and clock errors.
+		if _, err := s.Active(time.Time{}); err != nil {
+			return fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+		for _, d := range s.Days {
+			if _, ok := weekdays[strings.ToLower(d)]; !ok {
+				return fmt.Errorf("schedule %s: invalid day %q. Must be one of: Mon, Tue, Wed, Thu, Fri, Sat, Sun", s.Name, d)
+			}
+		}
+		overrides := []struct {
+			resource string
+			pressure *PSIPressure
+		}{
+			{"cpu", s.Thresholds.CPU},
+			{"memory", s.Thresholds.Memory},
+			{"io", s.Thresholds.IO},
+		}
+		for _, o := range overrides {
+			if o.pressure == nil {
+				continue
+			}
+			prefix := "schedule " + s.Name + ": " + o.resource
+			if err := validatePSIAverages(o.pressure.Some, prefix+".some"); err != nil {
+				return err
+			}
+			if err := validatePSIAverages(o.pressure.Full, prefix+".full"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
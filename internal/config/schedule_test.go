@@ -0,0 +1,106 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchedule_Active(t *testing.T) {
+	businessHours := Schedule{Name: "business", TimeZone: "Europe/Berlin", Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "08:00", End: "18:00"}
+	overnight := Schedule{Name: "overnight", Days: []string{"Fri"}, Start: "22:00", End: "06:00"}
+	allDay := Schedule{Name: "weekend", Days: []string{"sat", "sun"}}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		at       string
+		want     bool
+	}{
+		// 2026-10-16 is a Friday; Berlin is UTC+2 until 2026-10-25.
+		{name: "inside business hours", schedule: businessHours, at: "2026-10-16T07:30:00Z", want: true},
+		{name: "before business hours in local time", schedule: businessHours, at: "2026-10-16T05:30:00Z", want: false},
+		{name: "business hours on saturday", schedule: businessHours, at: "2026-10-17T10:00:00Z", want: false},
+		{name: "overnight start day", schedule: overnight, at: "2026-10-16T23:00:00Z", want: true},
+		{name: "overnight spills into saturday", schedule: overnight, at: "2026-10-17T05:59:00Z", want: true},
+		{name: "overnight ended", schedule: overnight, at: "2026-10-17T06:00:00Z", want: false},
+		{name: "overnight early friday belongs to thursday", schedule: overnight, at: "2026-10-16T03:00:00Z", want: false},
+		{name: "all day", schedule: allDay, at: "2026-10-18T12:00:00Z", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tt.schedule.Active(at)
+			if err != nil {
+				t.Fatalf("Active() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Active(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_Apply(t *testing.T) {
+	base := PSIThresholds{
+		CPU:    PSIPressure{Some: PSIAverages{Avg10: 25}},
+		Memory: PSIPressure{Some: PSIAverages{Avg10: 20}},
+	}
+	s := Schedule{Thresholds: ThresholdOverrides{CPU: &PSIPressure{Some: PSIAverages{Avg10: 60}}}}
+
+	got := s.Apply(base)
+	if got.CPU.Some.Avg10 != 60 {
+		t.Errorf("CPU.Some.Avg10 = %v, want 60", got.CPU.Some.Avg10)
+	}
+	if got.Memory.Some.Avg10 != 20 {
+		t.Errorf("Memory.Some.Avg10 = %v, want unchanged 20", got.Memory.Some.Avg10)
+	}
+	if base.CPU.Some.Avg10 != 25 {
+		t.Error("Apply() must not modify the base thresholds")
+	}
+}
+
+func TestValidateSchedules(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedules []Schedule
+		errMsg    string
+	}{
+		{name: "valid", schedules: []Schedule{{Name: "night", TimeZone: "America/New_York", Start: "20:00", End: "06:00"}}},
+		{name: "missing name", schedules: []Schedule{{Start: "20:00"}}, errMsg: "name must not be empty"},
+		{name: "reserved name", schedules: []Schedule{{Name: "default"}}, errMsg: "name default is reserved"},
+		{name: "duplicate name", schedules: []Schedule{{Name: "a"}, {Name: "a"}}, errMsg: "duplicate schedule name a"},
+		{name: "unknown time zone", schedules: []Schedule{{Name: "a", TimeZone: "Mars/Olympus"}}, errMsg: "invalid timeZone"},
+		{name: "bad clock", schedules: []Schedule{{Name: "a", Start: "8pm"}}, errMsg: "HH:MM"},
+		{name: "bad day", schedules: []Schedule{{Name: "a", Days: []string{"Mon", "Funday"}}}, errMsg: "invalid day"},
+		{
+			name:      "override out of range",
+			schedules: []Schedule{{Name: "a", Thresholds: ThresholdOverrides{IO: &PSIPressure{Full: PSIAverages{Avg60: 150}}}}},
+			errMsg:    "schedule a: io.full.avg60",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchedules(tt.schedules)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("validateSchedules() error = %v", err)
+				}
+				for _, s := range tt.schedules {
+					if s.loc == nil {
+						t.Errorf("schedule %s: time zone not resolved", s.Name)
+					}
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("validateSchedules() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaID identifies the JSON Schema of the configuration.
+const SchemaID = "https://github.com/Fedosin/kube-dethrottler/config.schema.json"
+
+// durationPattern matches the durations accepted by time.ParseDuration,
+// e.g. "30s" or "1h30m". Durations are strings: the YAML decoder rejects
+// integers, even 0.
+const durationPattern = `^[-+]?([0-9]*(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$|^0$`
+
+// schemaEnums lists the values allowed for the settings that take one of
+// a few values, keyed by their path in the configuration. "[]" stands for
+// the items of a list.
+var schemaEnums = map[string][]string{
+	"taintEffect":                 {"NoSchedule", "PreferNoSchedule", "NoExecute"},
+	"actuators[]":                 {ActuatorTaint, ActuatorCondition, ActuatorLabel, ActuatorAnnotation, ActuatorWebhook},
+	"profiles[].actuators[]":      {ActuatorTaint, ActuatorCondition, ActuatorLabel, ActuatorAnnotation, ActuatorWebhook},
+	"cloudEvents.mode":            {CloudEventsBinary, CloudEventsStructured},
+	"nodeLifecycle.notReady":      {LifecycleSkip, LifecycleUntaint, LifecycleEvaluate},
+	"nodeLifecycle.unschedulable": {LifecycleSkip, LifecycleUntaint, LifecycleEvaluate},
+	"nodeLifecycle.deleting":      {LifecycleSkip, LifecycleUntaint, LifecycleEvaluate},
+	"missingData.policy":          {MissingDataIgnore, MissingDataBreach, MissingDataUnknown},
+	"sources.order[]":             {SourceSummary, SourcePrometheus, SourceLastKnown},
+	"outliers.method":             {OutlierMAD, OutlierPercentile},
+	"logging.level":               {"debug", "info", "warn", "error"},
+	"logging.format":              {"text", "json"},
+}
+
+// Schema returns the JSON Schema of the configuration file, generated from
+// the yaml tags of Config. Objects reject unknown keys like Parse does;
+// semantic checks such as CEL rules and label selectors are left to
+// Validate.
+func Schema() map[string]any {
+	schema := schemaFor(reflect.TypeFor[Config](), "")
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["$id"] = SchemaID
+	schema["title"] = "kube-dethrottler configuration"
+	return schema
+}
+
+// HelmValuesSchema returns a JSON Schema for the chart's values, which
+// checks the config value against Schema and accepts any other value. Helm
+// validates values against it when it is saved as values.schema.json.
+func HelmValuesSchema() map[string]any {
+	config := Schema()
+	delete(config, "$schema")
+	delete(config, "$id")
+	return map[string]any{
+		"$schema":    "http://json-schema.org/draft-07/schema#",
+		"title":      "kube-dethrottler chart values",
+		"type":       "object",
+		"properties": map[string]any{"config": config},
+	}
+}
+
+func schemaFor(t reflect.Type, path string) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeFor[time.Duration]() {
+		return map[string]any{"type": "string", "pattern": durationPattern}
+	}
+
+	var schema map[string]any
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := map[string]any{}
+		for field := range t.Fields() {
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if !field.IsExported() || name == "-" || name == "" {
+				continue
+			}
+			properties[name] = schemaFor(field.Type, joinPath(path, name))
+		}
+		schema = map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+	case reflect.Slice:
+		schema = map[string]any{"type": "array", "items": schemaFor(t.Elem(), path+"[]")}
+	case reflect.String:
+		schema = map[string]any{"type": "string"}
+	case reflect.Bool:
+		schema = map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		schema = map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		schema = map[string]any{"type": "number"}
+	default:
+		schema = map[string]any{}
+	}
+	if enum, ok := schemaEnums[path]; ok {
+		schema["enum"] = enum
+	}
+	return schema
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSchema_Durations(t *testing.T) {
+	configProp := Schema()["properties"].(map[string]any)["pollInterval"].(map[string]any)
+	helmConfig := HelmValuesSchema()["properties"].(map[string]any)["config"].(map[string]any)
+	helmProp := helmConfig["properties"].(map[string]any)["pollInterval"].(map[string]any)
+
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "seconds", value: `"30s"`, valid: true},
+		{name: "compound", value: `"1m30s"`, valid: true},
+		{name: "fraction", value: `"1.5h"`, valid: true},
+		{name: "milliseconds", value: `"500ms"`, valid: true},
+		{name: "unquoted", value: `45s`, valid: true},
+		{name: "zero", value: `"0"`, valid: true},
+		{name: "integer", value: `30`},
+		{name: "no unit", value: `"30"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, prop := range map[string]map[string]any{"config": configProp, "helm": helmProp} {
+				if got := schemaAccepts(t, prop, tt.value); got != tt.valid {
+					t.Errorf("%s schema accepts %s = %v, want %v", name, tt.value, got, tt.valid)
+				}
+			}
+			// The range of pollInterval is left to Validate.
+			var cfg Config
+			err := decodeStrict([]byte("pollInterval: "+tt.value+"\n"), &cfg)
+			if (err == nil) != tt.valid {
+				t.Errorf("decodeStrict() with pollInterval %s error = %v, want valid %v", tt.value, err, tt.valid)
+			}
+		})
+	}
+}
+
+// schemaAccepts checks a scalar YAML value against the type and pattern of
+// a duration schema.
+func schemaAccepts(t *testing.T, prop map[string]any, value string) bool {
+	t.Helper()
+	if prop["type"] != "string" {
+		t.Fatalf("duration type = %v, want string", prop["type"])
+	}
+	s, quoted := value, false
+	if len(s) >= 2 && s[0] == '"' {
+		s, quoted = s[1:len(s)-1], true
+	}
+	if !quoted && regexp.MustCompile(`^[0-9]+$`).MatchString(s) {
+		// An unquoted number is an integer, not a string.
+		return false
+	}
+	return regexp.MustCompile(prop["pattern"].(string)).MatchString(s)
+}
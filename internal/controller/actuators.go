@@ -0,0 +1,349 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/Fedosin/kube-dethrottler/internal/actuator"
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/notify"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+// profile is a compiled config.Profile.
+type profile struct {
+	selector  labels.Selector
+	name      string
+	actuators []actuator.Actuator
+}
+
+// buildActuators creates the actuators configured in cfg.Actuators and
+// cfg.Profiles.
+func (c *Controller) buildActuators() {
+	c.actuators = c.newActuators(c.config.Actuators)
+	if len(c.actuators) == 0 {
+		c.actuators = c.newActuators([]string{config.ActuatorTaint})
+	}
+	for _, p := range c.config.Profiles {
+		selector, err := labels.Parse(p.NodeSelector)
+		if err != nil {
+			// Config.Validate rejects invalid selectors.
+			c.logger.Warn("Skipping profile with invalid nodeSelector", slog.String("profile", p.Name), logging.Err(err))
+			continue
+		}
+		c.profiles = append(c.profiles, profile{name: p.Name, selector: selector, actuators: c.newActuators(p.Actuators)})
+	}
+}
+
+func (c *Controller) newActuators(names []string) []actuator.Actuator {
+	acts := make([]actuator.Actuator, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case config.ActuatorTaint:
+			acts = append(acts, actuator.NewTaint(c.kubeClient, c.config.TaintKey, c.config.TaintEffect))
+		case config.ActuatorCondition:
+			acts = append(acts, actuator.NewCondition(c.kubeClient, c.config.NodeCondition.Type))
+		case config.ActuatorLabel:
+			acts = append(acts, actuator.NewLabel(c.kubeClient, c.config.NodeLabel.Key))
+		case config.ActuatorAnnotation:
+			acts = append(acts, actuator.NewAnnotation(c.kubeClient, c.config.NodeAnnotation.Key))
+		case config.ActuatorWebhook:
+			if webhook := c.webhookActuator(); webhook != nil {
+				acts = append(acts, webhook)
+			}
+		default:
+			// Config.Validate rejects unknown actuators.
+			c.logger.Warn("Skipping unknown actuator", logging.Action(name))
+		}
+	}
+	return acts
+}
+
+// webhookActuator returns the webhook actuator, shared by all profiles so
+// that their events are batched together.
+func (c *Controller) webhookActuator() *actuator.Webhook {
+	if c.webhook != nil {
+		return c.webhook
+	}
+	cfg := c.config.Webhook
+	notifier, err := notify.NewWebhook(notify.WebhookConfig{
+		URL:          cfg.URL,
+		HeadersFile:  cfg.HeadersFile,
+		BodyTemplate: cfg.BodyTemplate,
+		Timeout:      cfg.Timeout,
+		MaxRetries:   cfg.MaxAttempts - 1,
+		RetryBackoff: cfg.RetryBackoff,
+		BatchWindow:  cfg.BatchWindow,
+		MaxBatchSize: cfg.MaxBatchSize,
+	}, c.logger)
+	if err != nil {
+		// Config.Validate rejects invalid templates.
+		c.logger.Warn("Skipping webhook actuator", logging.Err(err))
+		return nil
+	}
+	c.webhook = actuator.NewWebhook(notifier, cfg.URL)
+	return c.webhook
+}
+
+// runners returns the actuators and sinks that work in the background.
+func (c *Controller) runners() []actuator.Runner {
+	var runners []actuator.Runner
+	seen := make(map[actuator.Actuator]bool)
+	all := c.actuators
+	for _, p := range c.profiles {
+		all = append(all[:len(all):len(all)], p.actuators...)
+	}
+	for _, a := range all {
+		if r, ok := a.(actuator.Runner); ok && !seen[a] {
+			seen[a] = true
+			runners = append(runners, r)
+		}
+	}
+	if c.cloudEvents != nil {
+		runners = append(runners, c.cloudEvents)
+	}
+	return runners
+}
+
+// actuatorsFor returns the actuators of the first profile matching the
+// node's labels, or the default actuators.
+func (c *Controller) actuatorsFor(node kubernetes.NodeInfo) []actuator.Actuator {
+	for _, p := range c.profiles {
+		if p.selector.Matches(labels.Set(node.Labels)) {
+			return p.actuators
+		}
+	}
+	return c.actuators
+}
+
+// describeActions names the actions for logs and Events, e.g.
+// "taint kube-dethrottler/high-load:NoSchedule, condition PSIPressure".
+func describeActions(acts []actuator.Actuator) string {
+	parts := make([]string, 0, len(acts))
+	for _, a := range acts {
+		parts = append(parts, a.Describe())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// eventReason returns the reason of the Events recorded when the actions
+// are applied or removed, e.g. "Applied": PressureTaintApplied when they
+// include the taint, PressureActionsApplied otherwise.
+func eventReason(acts []actuator.Actuator, verb string) string {
+	if slices.ContainsFunc(acts, func(a actuator.Actuator) bool { return a.Name() == actuator.NameTaint }) {
+		return "PressureTaint" + verb
+	}
+	return "PressureActions" + verb
+}
+
+// recordReason persists when and why a node came under pressure in
+// actuator.ReasonAnnotation.
+func (c *Controller) recordReason(ctx context.Context, nodeName string, d actuator.Decision) {
+	value, err := json.Marshal(actuator.PressureAnnotation{Since: d.Time.UTC(), Resource: d.Resource, Reason: d.Reason})
+	if err == nil {
+		err = c.kubeClient.SetNodeAnnotation(ctx, nodeName, actuator.ReasonAnnotation, string(value))
+	}
+	if err != nil {
+		c.logger.Error("Error setting annotation", logging.Node(nodeName), slog.String("annotation", actuator.ReasonAnnotation), logging.Err(err))
+	}
+}
+
+// clearReason removes actuator.ReasonAnnotation once a node is released.
+func (c *Controller) clearReason(ctx context.Context, nodeName string) {
+	if err := c.kubeClient.RemoveNodeAnnotation(ctx, nodeName, actuator.ReasonAnnotation); err != nil {
+		c.logger.Error("Error removing annotation", logging.Node(nodeName), slog.String("annotation", actuator.ReasonAnnotation), logging.Err(err))
+	}
+}
+
+// target returns the node as last observed, named nodeName.
+func target(nodeName string, state *nodeState) kubernetes.NodeInfo {
+	node := state.node
+	node.Name = nodeName
+	return node
+}
+
+// actuated returns the actions of acts already in place on a node: those
+// whose actuator observes them on the node and, if any does, the actions
+// that cannot be observed, such as webhooks, so that they are released
+// too. All of acts are in place only if the result has the same length.
+func actuated(ctx context.Context, acts []actuator.Actuator, node kubernetes.NodeInfo) ([]actuator.Actuator, error) {
+	var observed, unobservable []actuator.Actuator
+	for _, a := range acts {
+		o, ok := a.(actuator.Observer)
+		if !ok {
+			unobservable = append(unobservable, a)
+			continue
+		}
+		applied, err := o.Applied(ctx, node)
+		if err != nil {
+			return nil, err
+		}
+		if applied {
+			observed = append(observed, a)
+		}
+	}
+	if len(observed) == 0 {
+		return nil, nil
+	}
+	return mergeActuators(observed, unobservable), nil
+}
+
+// initActuators publishes the neutral state of the actuators that have one
+// on a node seen for the first time.
+func (c *Controller) initActuators(ctx context.Context, acts []actuator.Actuator, node kubernetes.NodeInfo) {
+	for _, a := range acts {
+		if i, ok := a.(actuator.Initializer); ok {
+			if err := i.Init(ctx, node); err != nil {
+				c.logger.Error("Error initialising action", logging.Node(node.Name), logging.Action(a.Describe()), logging.Err(err))
+			}
+		}
+	}
+}
+
+// decision describes the node's current breaches for the actuators.
+// taintedNodes is the number of nodes under pressure after the transition.
+func (c *Controller) decision(state *nodeState, taintedNodes int) actuator.Decision {
+	d := actuator.Decision{
+		Time:         c.now(),
+		Resource:     actuator.ResourceOther,
+		Busiest:      busiestResource(state.sample),
+		Reason:       describeBreaches(state),
+		TaintedNodes: taintedNodes,
+	}
+	dominant := dominantBreach(state)
+	for i, b := range state.breaches {
+		ab := actuator.Breach{
+			Name: b.name(), Resource: breachResource(b), Tier: b.kind, Window: b.window,
+			Value: b.value, Threshold: b.threshold,
+		}
+		if i == dominant {
+			d.Resource = ab.Resource
+			d.Breaches = append([]actuator.Breach{ab}, d.Breaches...)
+			continue
+		}
+		d.Breaches = append(d.Breaches, ab)
+	}
+	return d
+}
+
+// busiestResource returns the resource with the highest some avg10 in
+// sample, cpu if none is above 0.
+func busiestResource(sample *psi.NodePSI) string {
+	busiest, highest := psi.ResourceCPU, 0.0
+	if sample == nil {
+		return busiest
+	}
+	for _, r := range []string{psi.ResourceCPU, psi.ResourceMemory, psi.ResourceIO} {
+		if sample.IsMissing(r) {
+			continue
+		}
+		if v := sample.ForResource(r).Some.Avg10; v > highest {
+			busiest, highest = r, v
+		}
+	}
+	return busiest
+}
+
+// breachResource returns the resource a breach is measured on, or "" for
+// breaches not tied to one. Trend and outlier signals count for the
+// resource of their metric, e.g. "cpu.some.avg10.rise" for cpu.
+func breachResource(b breach) string {
+	if b.resource != "" {
+		return b.resource
+	}
+	resource, _, _ := strings.Cut(b.signal, ".")
+	if resource != "cpu" && resource != "memory" && resource != "io" {
+		return ""
+	}
+	return resource
+}
+
+// dominantBreach returns the index of the resource breach that exceeds its
+// threshold the most, or -1 if no breach is tied to a resource.
+func dominantBreach(state *nodeState) int {
+	best, bestRatio := -1, 0.0
+	for i, b := range state.breaches {
+		if breachResource(b) == "" {
+			continue
+		}
+		ratio := b.severity()
+		if best < 0 || ratio > bestRatio {
+			best, bestRatio = i, ratio
+		}
+	}
+	return best
+}
+
+// taintedNodes counts the nodes under pressure.
+func (c *Controller) taintedNodes() int {
+	n := 0
+	for _, state := range c.nodes {
+		if state.tainted {
+			n++
+		}
+	}
+	return n
+}
+
+// actuatePressure runs every action in order and returns those that
+// succeeded along with the failures of the others. Actions are idempotent
+// and retried on the next poll.
+func actuatePressure(ctx context.Context, acts []actuator.Actuator, node kubernetes.NodeInfo, d actuator.Decision) ([]actuator.Actuator, error) {
+	var applied []actuator.Actuator
+	var errs []error
+	for _, a := range acts {
+		if err := a.OnPressure(ctx, node, d); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", a.Name(), err))
+			continue
+		}
+		applied = append(applied, a)
+	}
+	return applied, errors.Join(errs...)
+}
+
+// refreshActions updates the actions that follow the decision while
+// pressure lasts.
+func (c *Controller) refreshActions(ctx context.Context, acts []actuator.Actuator, node kubernetes.NodeInfo, d actuator.Decision) {
+	for _, a := range acts {
+		if r, ok := a.(actuator.Refresher); ok {
+			if err := r.Refresh(ctx, node, d); err != nil {
+				c.logger.Error("Error refreshing action", logging.Node(node.Name), logging.Action(a.Describe()), logging.Err(err))
+			}
+		}
+	}
+}
+
+// actuateRecovered reverts every action in order and returns those still
+// in place along with their failures.
+func actuateRecovered(ctx context.Context, acts []actuator.Actuator, node kubernetes.NodeInfo, d actuator.Decision) ([]actuator.Actuator, error) {
+	var remaining []actuator.Actuator
+	var errs []error
+	for _, a := range acts {
+		if err := a.OnRecovered(ctx, node, d); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", a.Name(), err))
+			remaining = append(remaining, a)
+		}
+	}
+	return remaining, errors.Join(errs...)
+}
+
+// mergeActuators returns applied followed by the actuators of acts it does
+// not hold yet.
+func mergeActuators(applied, acts []actuator.Actuator) []actuator.Actuator {
+	merged := slices.Clone(applied)
+	for _, a := range acts {
+		if !slices.Contains(merged, a) {
+			merged = append(merged, a)
+		}
+	}
+	return merged
+}
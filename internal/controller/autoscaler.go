@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+const (
+	// ScaleDownDisabledAnnotation prevents cluster-autoscaler from removing a node.
+	ScaleDownDisabledAnnotation = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
+	// ScaleDownOwnerAnnotation marks scale-down annotations set by the
+	// controller, so that annotations set by others are left in place.
+	ScaleDownOwnerAnnotation = "kube-dethrottler.io/scale-down-disabled"
+)
+
+// disableScaleDown annotates a newly tainted node so that cluster-autoscaler
+// does not remove it, unless the node already carries the annotation.
+func (c *Controller) disableScaleDown(ctx context.Context, nodeName string, state *nodeState) {
+	if !c.config.Autoscaler.DisableScaleDown {
+		return
+	}
+	if _, exists := state.annotations[ScaleDownDisabledAnnotation]; exists && state.annotations[ScaleDownOwnerAnnotation] == "" {
+		return
+	}
+	// Both annotations go in one patch, so that the scale-down annotation is
+	// never left behind without the marker that the controller owns it.
+	value := "true"
+	annotations := map[string]*string{ScaleDownDisabledAnnotation: &value, ScaleDownOwnerAnnotation: &value}
+	if err := c.kubeClient.PatchNodeAnnotations(ctx, nodeName, annotations); err != nil {
+		c.logger.Error("Error disabling scale-down", logging.Node(nodeName), logging.Err(err))
+		return
+	}
+	state.scaleDownDisabled = true
+}
+
+// restoreScaleDown removes the scale-down annotation from an untainted
+// node if the controller set it.
+func (c *Controller) restoreScaleDown(ctx context.Context, nodeName string, state *nodeState) {
+	if !state.scaleDownDisabled && state.annotations[ScaleDownOwnerAnnotation] == "" {
+		return
+	}
+	annotations := map[string]*string{ScaleDownDisabledAnnotation: nil, ScaleDownOwnerAnnotation: nil}
+	if err := c.kubeClient.PatchNodeAnnotations(ctx, nodeName, annotations); err != nil {
+		c.logger.Error("Error re-enabling scale-down", logging.Node(nodeName), logging.Err(err))
+		return
+	}
+	state.scaleDownDisabled = false
+	delete(state.annotations, ScaleDownOwnerAnnotation)
+}
+
+// reportTaintedCapacity exports, per pool, how many nodes and how much
+// allocatable capacity are withheld by pressure taints, so that autoscalers
+// can add capacity when too much of a pool is tainted.
+func (c *Controller) reportTaintedCapacity(nodes []kubernetes.NodeInfo) {
+	type pool struct {
+		capacity map[string]float64
+		total    int
+		tainted  int
+	}
+	pools := make(map[string]*pool)
+	for _, node := range nodes {
+		name := "all"
+		if c.config.Autoscaler.PoolLabel != "" {
+			name = node.Labels[c.config.Autoscaler.PoolLabel]
+		}
+		p, ok := pools[name]
+		if !ok {
+			p = &pool{capacity: map[string]float64{"cpu": 0, "memory": 0}}
+			pools[name] = p
+		}
+		p.total++
+		if state := c.nodes[node.Name]; state != nil && state.tainted {
+			p.tainted++
+			for resource, v := range node.Allocatable {
+				p.capacity[resource] += v
+			}
+		}
+	}
+
+	metrics.PoolNodes.Reset()
+	metrics.PoolTaintedCapacity.Reset()
+	metrics.PoolTaintedRatio.Reset()
+	for name, p := range pools {
+		metrics.PoolNodes.WithLabelValues(name, "total").Set(float64(p.total))
+		metrics.PoolNodes.WithLabelValues(name, "tainted").Set(float64(p.tainted))
+		metrics.PoolTaintedRatio.WithLabelValues(name).Set(float64(p.tainted) / float64(p.total))
+		for resource, v := range p.capacity {
+			metrics.PoolTaintedCapacity.WithLabelValues(name, resource).Set(v)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+// recordTaintCycle counts a new taint cycle on the node and computes the
+// cooldown it must wait before the taint may be removed again.
+func (c *Controller) recordTaintCycle(nodeName string, state *nodeState) {
+	backoff := c.config.CooldownBackoff
+	if !backoff.Enabled {
+		return
+	}
+
+	// Forget one cycle for every quiet period since the last untaint.
+	if !state.untaintedAt.IsZero() && backoff.QuietPeriod > 0 {
+		quiet := int(c.now().Sub(state.untaintedAt) / backoff.QuietPeriod)
+		state.cycles = max(state.cycles-quiet, 0)
+	}
+	state.cycles++
+
+	cooldown := float64(c.config.CooldownPeriod) * math.Pow(backoff.Multiplier, float64(state.cycles-1))
+	state.cooldown = time.Duration(math.Min(cooldown, float64(backoff.MaxCooldown)))
+	metrics.EffectiveCooldown.WithLabelValues(nodeName).Set(state.cooldown.Seconds())
+	if state.cycles > 1 {
+		c.logger.Info("Cooldown extended", logging.Node(nodeName), slog.Int("cycle", state.cycles), slog.Duration("cooldown", state.cooldown))
+	}
+}
+
+// effectiveCooldown returns the cooldown in effect for the node's taint.
+func (c *Controller) effectiveCooldown(state *nodeState) time.Duration {
+	if !c.config.CooldownBackoff.Enabled || state.cooldown == 0 {
+		return c.config.CooldownPeriod
+	}
+	return state.cooldown
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+)
+
+// CheckNodes fetches the PSI of each node once and evaluates it like a
+// poll would, under the schedule active now, without acting on the result
+// or recording Events. Smoothing, trends and outliers need earlier polls
+// or the whole pool and are not applied. An evaluation's Fired checks are
+// non-empty if the node would be put under pressure; Tainted reports
+// whether it already is.
+func (c *Controller) CheckNodes(ctx context.Context, nodes []kubernetes.NodeInfo) []Evaluation {
+	c.resolveSchedule()
+	evaluations := make([]Evaluation, 0, len(nodes))
+	for _, node := range nodes {
+		evaluations = append(evaluations, *c.dryRunNode(ctx, node))
+	}
+	return evaluations
+}
+
+func (c *Controller) dryRunNode(ctx context.Context, node kubernetes.NodeInfo) *Evaluation {
+	acts := c.actuatorsFor(node)
+	state := &nodeState{node: node, conditions: node.Conditions}
+	s := sampledNode{state: state, name: node.Name, labels: node.Labels}
+	result := func(skipped string, err error) *Evaluation {
+		e := c.evaluation(s)
+		e.Skipped = skipped
+		if err != nil {
+			e.Error = err.Error()
+		}
+		return e
+	}
+
+	state.action = Action{Actions: describeActions(acts), Result: ResultNone}
+	applied, err := actuated(ctx, acts, node)
+	if err != nil {
+		return result("", err)
+	}
+	state.tainted = len(applied) == len(acts)
+	state.applied = applied
+
+	if lifecycle, policy := c.lifecycleState(node); lifecycle != "" && policy != config.LifecycleEvaluate {
+		return result(lifecycle, nil)
+	}
+
+	fetchFn := c.psiFetcher.FetchNodePSI
+	if c.psiFetchFunc != nil {
+		fetchFn = c.psiFetchFunc
+	}
+	nodePSI, err := fetchFn(ctx, node.Name)
+	if err != nil {
+		return result("", err)
+	}
+
+	unavailable, _ := c.unavailableResources(nodePSI)
+	if len(unavailable) > 0 {
+		switch c.config.MissingData.Policy {
+		case config.MissingDataBreach:
+			s.dataBreach = true
+		case config.MissingDataUnknown:
+			state.sample = nodePSI
+			return result("unknown", nil)
+		default:
+			nodePSI = withoutResources(nodePSI, unavailable)
+		}
+	}
+
+	state.sample = nodePSI
+	state.breaches = c.evaluate(nodePSI, state, node.Name)
+	return c.evaluation(s)
+}
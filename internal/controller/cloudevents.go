@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"github.com/Fedosin/kube-dethrottler/internal/actuator"
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/notify"
+)
+
+// newCloudEvents creates the CloudEvents sink, or returns nil when
+// CloudEvents are disabled.
+func (c *Controller) newCloudEvents() *notify.CloudEvents {
+	cfg := c.config.CloudEvents
+	if !cfg.Enabled {
+		return nil
+	}
+	mode := notify.ModeBinary
+	if cfg.Mode == config.CloudEventsStructured {
+		mode = notify.ModeStructured
+	}
+	return notify.NewCloudEvents(notify.CloudEventsConfig{
+		URL:          cfg.URL,
+		Mode:         mode,
+		Source:       cfg.Source,
+		HeadersFile:  cfg.HeadersFile,
+		Timeout:      cfg.Timeout,
+		MaxRetries:   cfg.MaxAttempts - 1,
+		RetryBackoff: cfg.RetryBackoff,
+	}, c.logger)
+}
+
+// emitPollFailed reports a failed node listing (nodeName empty) or PSI
+// fetch.
+func (c *Controller) emitPollFailed(nodeName, stage string, err error) {
+	if c.cloudEvents == nil {
+		return
+	}
+	c.cloudEvents.Emit(notify.TypePollFailed, nodeName, c.now(),
+		notify.PollFailedData{Node: nodeName, Stage: stage, Error: err.Error()})
+}
+
+// emitPressureDetected reports a node whose evaluation found breaches
+// after one that did not.
+func (c *Controller) emitPressureDetected(s sampledNode) {
+	breached := len(s.state.breaches) > 0 || s.dataBreach
+	detected := breached && !s.state.pressureDetected
+	s.state.pressureDetected = breached
+	if c.cloudEvents == nil || !detected {
+		return
+	}
+	d := c.decision(s.state, c.taintedNodes())
+	c.cloudEvents.Emit(notify.TypePressureDetected, s.name, d.Time, notify.NodePressureData{
+		Node: s.name, Resource: d.Resource, Reason: d.Reason, Breaches: d.NotifyBreaches(), TaintedNodes: d.TaintedNodes,
+	})
+}
+
+// emitTainted reports that the pressure actions were applied to a node.
+func (c *Controller) emitTainted(nodeName, actions string, d actuator.Decision) {
+	if c.cloudEvents == nil {
+		return
+	}
+	c.cloudEvents.Emit(notify.TypeTainted, nodeName, d.Time, notify.NodePressureData{
+		Node: nodeName, Resource: d.Resource, Reason: d.Reason, Actions: actions,
+		Breaches: d.NotifyBreaches(), TaintedNodes: d.TaintedNodes,
+	})
+}
+
+// emitUntainted reports that the pressure actions were released from a
+// node.
+func (c *Controller) emitUntainted(nodeName, actions string, state *nodeState, d actuator.Decision) {
+	if c.cloudEvents == nil {
+		return
+	}
+	c.cloudEvents.Emit(notify.TypeUntainted, nodeName, d.Time, notify.NodeUntaintedData{
+		Node: nodeName, Reason: d.Reason, Actions: actions,
+		TaintedSeconds: d.Time.Sub(state.taintedSince).Seconds(), TaintedNodes: d.TaintedNodes,
+	})
+}
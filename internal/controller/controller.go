@@ -2,52 +2,162 @@ package controller
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"math"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Fedosin/kube-dethrottler/internal/actuator"
 	"github.com/Fedosin/kube-dethrottler/internal/config"
 	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+	"github.com/Fedosin/kube-dethrottler/internal/notify"
 	"github.com/Fedosin/kube-dethrottler/internal/psi"
+	"github.com/Fedosin/kube-dethrottler/internal/rules"
 )
 
 type nodeState struct {
 	lastTaintTime time.Time
-	tainted       bool
+	// taintedSince is when the current taint was applied or first observed.
+	taintedSince time.Time
+	lastEviction time.Time
+	// evictionBlockedAt is when an eviction was last refused, e.g. by a
+	// PodDisruptionBudget.
+	evictionBlockedAt time.Time
+	// untaintedAt is when the taint was last removed. cycles counts recent
+	// taint cycles for the cooldown backoff, and cooldown is the cooldown
+	// in effect for the current taint.
+	untaintedAt time.Time
+	cycles      int
+	cooldown    time.Duration
+	// smoothed holds the latest moving average of each resource and
+	// smoothedAt when each was last updated.
+	smoothed   *psi.NodePSI
+	smoothedAt map[string]time.Time
+	// annotations holds the node's annotations observed at the latest poll.
+	annotations map[string]string
+	// node is the node as observed at the latest poll.
+	node kubernetes.NodeInfo
+	// scaleDownDisabled is set while the controller holds the
+	// cluster-autoscaler scale-down annotation on the node.
+	scaleDownDisabled bool
+	// unavailable holds the monitored resources whose PSI data was missing
+	// or stale in the previous sample.
+	unavailable []string
+	// sample and breaches hold the latest evaluated sample and the
+	// thresholds it exceeded.
+	sample   *psi.NodePSI
+	breaches []breach
+	// topPods is the last reported list of top pressure-contributing pods.
+	topPods string
+	// history holds the most recent evaluated samples, oldest first, for
+	// trend triggers.
+	history []*psi.NodePSI
+	// conditions holds the node conditions observed at the latest poll.
+	conditions map[string]bool
+	// source is the PSI source that supplied the previous sample.
+	source string
+	// pressureDetected is set while the node's evaluations find breaches.
+	pressureDetected bool
+	// tainted is set while the pressure actions (the taint and any other
+	// configured actuators) are applied to the node.
+	tainted bool
+	// applied holds the actuators whose action is in place on the node,
+	// which may be only some of them after a failure. They are the ones
+	// released, even if the node has since moved to another profile.
+	applied []actuator.Actuator
+	unknown bool
+	// action is the result of the pressure actions at the latest poll.
+	action Action
 }
 
 // Controller manages the main loop of fetching PSI metrics, checking thresholds,
 // and tainting overloaded nodes.
 type Controller struct {
 	kubeClient   kubernetes.KubeClientInterface
-	psiFetcher   *psi.Fetcher
+	psiFetcher   psi.Source
 	psiFetchFunc func(ctx context.Context, nodeName string) (*psi.NodePSI, error)
 	config       *config.Config
-	logger       *log.Logger
+	logger       *slog.Logger
 	nodes        map[string]*nodeState
+	// skipped maps nodes skipped for their lifecycle state to that state.
+	skipped      map[string]string
+	rules        []*rules.Rule
+	lastEviction time.Time
+	// actuators are the default pressure actions; profiles override them
+	// for the nodes they select.
+	actuators []actuator.Actuator
+	profiles  []profile
+	webhook   *actuator.Webhook
+	// cloudEvents is nil unless CloudEvents are enabled.
+	cloudEvents *notify.CloudEvents
+	// historySize is the number of samples kept per node for trend triggers.
+	historySize int
+	// schedule is the active threshold schedule, nil when the base
+	// thresholds apply.
+	schedule *config.Schedule
+	now      func() time.Time
+	// evaluations holds the latest evaluation of each node for the debug
+	// endpoints, which read it concurrently with the poll loop.
+	evaluations   map[string]*Evaluation
+	evaluationsMu sync.RWMutex
+	// paused is set while the pause switch suspends changes to nodes.
+	paused bool
 }
 
 // NewController creates a new Controller instance.
-func NewController(cfg *config.Config, kubeClient kubernetes.KubeClientInterface, psiFetcher *psi.Fetcher, logger *log.Logger) *Controller {
-	return &Controller{
+func NewController(cfg *config.Config, kubeClient kubernetes.KubeClientInterface, psiFetcher psi.Source, logger *slog.Logger) *Controller {
+	c := &Controller{
 		config:     cfg,
 		kubeClient: kubeClient,
 		psiFetcher: psiFetcher,
 		logger:     logger,
 		nodes:      make(map[string]*nodeState),
+		skipped:    make(map[string]string),
+		now:        time.Now,
+
+		evaluations: make(map[string]*Evaluation),
+	}
+	for _, r := range cfg.Thresholds.Rules {
+		rule, err := rules.Compile(r.Name, r.Expression)
+		if err != nil {
+			// Config.Validate rejects rules that do not compile.
+			logger.Warn("Skipping rule", logging.Err(err))
+			continue
+		}
+		c.rules = append(c.rules, rule)
+	}
+	for _, r := range cfg.Thresholds.Trends.Rise {
+		c.historySize = max(c.historySize, risePolls(r))
 	}
+	c.buildActuators()
+	c.cloudEvents = c.newCloudEvents()
+	return c
 }
 
 // Run starts the main loop of the controller.
 func (c *Controller) Run(ctx context.Context) {
-	c.logger.Printf("Starting kube-dethrottler (PSI mode)")
-	c.logger.Printf("Poll Interval: %s", c.config.PollInterval)
-	c.logger.Printf("Cooldown Period: %s", c.config.CooldownPeriod)
-	c.logger.Printf("Taint Key: %s, Effect: %s", c.config.TaintKey, c.config.TaintEffect)
-	if c.config.NodeFilter != "" {
-		c.logger.Printf("Node Filter: %s", c.config.NodeFilter)
+	c.logger.Info("Starting kube-dethrottler (PSI mode)",
+		slog.Duration("pollInterval", c.config.PollInterval),
+		slog.Duration("cooldownPeriod", c.config.CooldownPeriod),
+		logging.Action(describeActions(c.actuators)),
+		slog.String("nodeFilter", c.config.NodeFilter))
+
+	// Background actuators outlive ctx so that the events of the shutdown
+	// cleanup are still delivered.
+	runCtx, stopRunners := context.WithCancel(context.WithoutCancel(ctx))
+	var runners sync.WaitGroup
+	for _, r := range c.runners() {
+		runners.Go(func() { r.Run(runCtx) })
 	}
 
 	ticker := time.NewTicker(c.config.PollInterval)
@@ -59,8 +169,10 @@ func (c *Controller) Run(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			c.logger.Println("Shutting down controller...")
+			c.logger.Info("Shutting down controller")
 			c.cleanupTaints()
+			stopRunners()
+			runners.Wait()
 			return
 		case <-ticker.C:
 			c.pollAllNodes(ctx)
@@ -68,152 +180,541 @@ func (c *Controller) Run(ctx context.Context) {
 	}
 }
 
+// Poll polls all nodes once and acts on the results, like every tick of
+// Run. Background actuators such as webhook batching are not started.
+func (c *Controller) Poll(ctx context.Context) {
+	c.pollAllNodes(ctx)
+}
+
+// SetClock replaces the controller's clock, e.g. to replay recorded
+// samples in simulated time. It must be called before the first poll.
+func (c *Controller) SetClock(now func() time.Time) {
+	c.now = now
+}
+
 func (c *Controller) cleanupTaints() {
+	if c.paused {
+		c.logger.Warn("Paused, leaving the actions in place on shutdown")
+		return
+	}
 	for nodeName, state := range c.nodes {
-		if state.tainted {
-			c.logger.Printf("Attempting to remove taint %s from node %s on shutdown...", c.config.TaintKey, nodeName)
-			err := c.kubeClient.RemoveTaint(context.Background(), nodeName, c.config.TaintKey, c.config.TaintEffect)
-			if err != nil {
-				c.logger.Printf("Failed to remove taint from node %s on shutdown: %v", nodeName, err)
-			} else {
-				c.logger.Printf("Taint %s removed from node %s on shutdown.", c.config.TaintKey, nodeName)
-			}
+		if len(state.applied) > 0 {
+			c.logger.Info("Releasing node on shutdown", logging.Node(nodeName),
+				logging.Action(describeActions(state.applied)))
+			c.release(context.Background(), nodeName, state, "on shutdown")
 		}
 	}
 }
 
 func (c *Controller) pollAllNodes(ctx context.Context) {
-	nodeNames, err := c.kubeClient.ListNodeNames(ctx, c.config.NodeFilter)
+	nodes, err := c.kubeClient.ListNodes(ctx, c.config.NodeFilter)
 	if err != nil {
-		c.logger.Printf("Error listing nodes: %v", err)
+		c.logger.Error("Error listing nodes", logging.Err(err))
+		c.emitPollFailed("", "list", err)
 		return
 	}
 
+	c.resolveSchedule()
+
 	// Clean up state for nodes that no longer exist
 	for name := range c.nodes {
 		found := false
-		for _, n := range nodeNames {
-			if n == name {
+		for _, n := range nodes {
+			if n.Name == name {
 				found = true
 				break
 			}
 		}
 		if !found {
 			delete(c.nodes, name)
+			delete(c.skipped, name)
 		}
 	}
+	c.pruneEvaluations(nodes)
 
-	for _, nodeName := range nodeNames {
-		c.checkNode(ctx, nodeName)
+	if c.updatePaused(ctx, nodes) {
+		c.pollPaused(ctx, nodes)
+		return
 	}
-}
 
-func (c *Controller) checkNode(ctx context.Context, nodeName string) {
-	state, exists := c.nodes[nodeName]
-	if !exists {
-		hasTaint, err := c.kubeClient.HasTaint(ctx, nodeName, c.config.TaintKey, c.config.TaintEffect)
-		if err != nil {
-			c.logger.Printf("Error checking taint on node %s: %v", nodeName, err)
-			return
+	// Sample every node before deciding, so that pool-relative checks
+	// see the whole pass.
+	var pass []sampledNode
+	for _, node := range nodes {
+		if s, ok := c.sampleNode(ctx, node); ok {
+			pass = append(pass, s)
 		}
-		state = &nodeState{tainted: hasTaint}
-		if hasTaint {
-			state.lastTaintTime = time.Now()
-			c.logger.Printf("Node %s already has taint %s", nodeName, c.config.TaintKey)
-		}
-		c.nodes[nodeName] = state
 	}
 
+	if c.config.Outliers.Enabled {
+		c.detectOutliers(pass)
+	}
+
+	for _, s := range pass {
+		c.decide(ctx, s)
+	}
+
+	c.reportTaintedCapacity(nodes)
+}
+
+// sampledNode is a node whose PSI sample was fetched and evaluated during
+// a poll, pending the taint decision.
+type sampledNode struct {
+	state *nodeState
+	name  string
+	// labels are the node's labels, used to group nodes into pools.
+	labels map[string]string
+	// dataBreach is set when unavailable data counts as a breach.
+	dataBreach bool
+}
+
+// sampleNode fetches and evaluates the node's PSI sample. It returns false
+// if the node must be skipped for this poll.
+func (c *Controller) sampleNode(ctx context.Context, node kubernetes.NodeInfo) (sampledNode, bool) {
+	nodeName := node.Name
+	if c.skipForLifecycle(ctx, node) {
+		c.recordSkipped(nodeName, c.skipped[nodeName], nil)
+		return sampledNode{}, false
+	}
+	state, ok := c.nodeState(ctx, node)
+	if !ok {
+		return sampledNode{}, false
+	}
+	state.node = node
+	state.conditions = node.Conditions
+	state.annotations = node.Annotations
+
 	fetchFn := c.psiFetcher.FetchNodePSI
 	if c.psiFetchFunc != nil {
 		fetchFn = c.psiFetchFunc
 	}
 	nodePSI, err := fetchFn(ctx, nodeName)
 	if err != nil {
-		c.logger.Printf("Error fetching PSI for node %s: %v", nodeName, err)
-		return
+		c.logger.Error("Error fetching PSI", logging.Node(nodeName), logging.Err(err))
+		metrics.PollErrors.WithLabelValues(nodeName, "fetch").Inc()
+		c.emitPollFailed(nodeName, "fetch", err)
+		c.recordSkipped(nodeName, "", err)
+		return sampledNode{}, false
+	}
+	c.recordSource(nodeName, state, nodePSI.Source)
+
+	nodePSI, breach, skip := c.applyMissingDataPolicy(ctx, nodeName, state, nodePSI)
+	if skip {
+		c.recordSkipped(nodeName, "unknown", nil)
+		return sampledNode{}, false
 	}
 
-	exceeded := c.isThresholdExceeded(nodePSI, nodeName)
+	nodePSI = c.smooth(nodeName, state, nodePSI)
+	state.sample = nodePSI
+	c.recordHistory(state, nodePSI)
+	state.breaches = c.evaluate(nodePSI, state, nodeName)
+
+	return sampledNode{state: state, name: nodeName, labels: node.Labels, dataBreach: breach}, true
+}
+
+// nodeState returns the node's state, creating it from the actions
+// already visible on the node on first sight. A node with only some of
+// its actions in place is partially applied: the rest are applied if it is
+// still under pressure, and those in place are reverted otherwise. It
+// returns false if the actions cannot be checked.
+func (c *Controller) nodeState(ctx context.Context, node kubernetes.NodeInfo) (*nodeState, bool) {
+	nodeName := node.Name
+	if state, exists := c.nodes[nodeName]; exists {
+		return state, true
+	}
+	acts := c.actuatorsFor(node)
+	applied, err := actuated(ctx, acts, node)
+	if err != nil {
+		c.logger.Error("Error checking actions", logging.Node(nodeName), logging.Action(describeActions(acts)), logging.Err(err))
+		c.recordSkipped(nodeName, "", err)
+		return nil, false
+	}
+	state := &nodeState{tainted: len(applied) == len(acts), applied: applied, node: node}
+	switch {
+	case state.tainted:
+		state.lastTaintTime = c.now()
+		state.taintedSince = state.lastTaintTime
+		c.logger.Info("Actions already applied", logging.Node(nodeName), logging.Action(describeActions(acts)))
+	case len(applied) > 0:
+		c.logger.Info("Actions partially applied", logging.Node(nodeName), logging.Action(describeActions(applied)))
+	default:
+		c.initActuators(ctx, acts, node)
+	}
+	c.nodes[nodeName] = state
+	return state, true
+}
 
-	if exceeded {
-		c.handleExceeded(ctx, nodeName, state)
+// decide taints or untaints a sampled node based on its breaches.
+func (c *Controller) decide(ctx context.Context, s sampledNode) {
+	c.emitPressureDetected(s)
+	s.state.action = Action{Actions: describeActions(c.actuatorsFor(s.state.node)), Result: ResultNone}
+	if len(s.state.breaches) > 0 || s.dataBreach {
+		c.handleExceeded(ctx, s.name, s.state)
 	} else {
-		c.handleNotExceeded(ctx, nodeName, state)
+		c.handleNotExceeded(ctx, s.name, s.state)
 	}
+	c.recordEvaluation(s)
 }
 
-func (c *Controller) isThresholdExceeded(nodePSI *psi.NodePSI, nodeName string) bool {
-	return c.checkAverages(nodePSI.CPU.Some, c.config.Thresholds.CPU.Some, nodeName, "cpu.some") ||
-		c.checkAverages(nodePSI.CPU.Full, c.config.Thresholds.CPU.Full, nodeName, "cpu.full") ||
-		c.checkAverages(nodePSI.Memory.Some, c.config.Thresholds.Memory.Some, nodeName, "memory.some") ||
-		c.checkAverages(nodePSI.Memory.Full, c.config.Thresholds.Memory.Full, nodeName, "memory.full") ||
-		c.checkAverages(nodePSI.IO.Some, c.config.Thresholds.IO.Some, nodeName, "io.some") ||
-		c.checkAverages(nodePSI.IO.Full, c.config.Thresholds.IO.Full, nodeName, "io.full")
+// recordSource counts samples per source and logs whenever the source
+// supplying a node's samples changes, e.g. on fallback and recovery.
+func (c *Controller) recordSource(nodeName string, state *nodeState, source string) {
+	if source == "" {
+		return
+	}
+	metrics.PSISamples.WithLabelValues(nodeName, source).Inc()
+	if state.source != "" && state.source != source {
+		c.logger.Info("PSI source changed", logging.Node(nodeName), slog.String("from", state.source), slog.String("to", source))
+	}
+	state.source = source
+}
+
+// applyMissingDataPolicy reconciles missing or stale PSI data with the
+// configured policy. It returns the sample to evaluate, whether unavailable
+// data counts as a threshold breach, and whether the node should be skipped
+// because its pressure state is unknown.
+func (c *Controller) applyMissingDataPolicy(ctx context.Context, nodeName string, state *nodeState, nodePSI *psi.NodePSI) (sample *psi.NodePSI, breach, skip bool) {
+	unavailable, reason := c.unavailableResources(nodePSI)
+	c.reportDataAvailability(ctx, nodeName, state, nodePSI, unavailable, reason)
+
+	policy := c.config.MissingData.Policy
+	unknown := len(unavailable) > 0 && policy == config.MissingDataUnknown
+	if unknown != state.unknown {
+		state.unknown = unknown
+		metrics.NodeUnknown.WithLabelValues(nodeName).Set(boolToFloat(unknown))
+	}
+
+	if len(unavailable) == 0 {
+		return nodePSI, false, false
+	}
+
+	switch policy {
+	case config.MissingDataBreach:
+		c.logger.Warn("PSI data unavailable, treating as threshold breach", logging.Node(nodeName),
+			logging.Resource(strings.Join(unavailable, ",")), slog.String("reason", reason))
+		return nodePSI, true, false
+	case config.MissingDataUnknown:
+		c.logger.Warn("PSI data unavailable, pressure state unknown; leaving actions unchanged", logging.Node(nodeName),
+			logging.Resource(strings.Join(unavailable, ",")), slog.String("reason", reason))
+		return nil, false, true
+	default:
+		return withoutResources(nodePSI, unavailable), false, false
+	}
+}
+
+// unavailableResources returns the monitored resources whose data cannot be
+// trusted, along with the reason ("missing" or "stale").
+func (c *Controller) unavailableResources(nodePSI *psi.NodePSI) (resources []string, reason string) {
+	maxAge := c.config.MissingData.MaxSampleAge
+	stale := maxAge > 0 && !nodePSI.Timestamp.IsZero() && c.now().Sub(nodePSI.Timestamp) > maxAge
+
+	for _, r := range c.monitoredResources() {
+		if stale || nodePSI.IsMissing(r) {
+			resources = append(resources, r)
+		}
+	}
+	if stale {
+		return resources, "stale"
+	}
+	return resources, "missing"
+}
+
+// monitoredResources returns the resources that have at least one threshold set.
+func (c *Controller) monitoredResources() []string {
+	var resources []string
+	t := c.thresholds()
+	if t.CPU.Enabled() {
+		resources = append(resources, psi.ResourceCPU)
+	}
+	if t.Memory.Enabled() {
+		resources = append(resources, psi.ResourceMemory)
+	}
+	if t.IO.Enabled() {
+		resources = append(resources, psi.ResourceIO)
+	}
+	return resources
+}
+
+// reportDataAvailability updates data availability metrics and records an
+// Event whenever the set of unavailable resources changes.
+func (c *Controller) reportDataAvailability(ctx context.Context, nodeName string, state *nodeState, nodePSI *psi.NodePSI, unavailable []string, reason string) {
+	if !nodePSI.Timestamp.IsZero() {
+		metrics.PSISampleAge.WithLabelValues(nodeName).Set(c.now().Sub(nodePSI.Timestamp).Seconds())
+	}
+	for _, r := range []string{psi.ResourceCPU, psi.ResourceMemory, psi.ResourceIO} {
+		missing := nodePSI.IsMissing(r) || (reason == "stale" && slices.Contains(unavailable, r))
+		metrics.PSIDataMissing.WithLabelValues(nodeName, r).Set(boolToFloat(missing))
+	}
+
+	if strings.Join(unavailable, ",") == strings.Join(state.unavailable, ",") {
+		return
+	}
+	state.unavailable = unavailable
+
+	if len(unavailable) == 0 {
+		c.recordEvent(ctx, nodeName, corev1.EventTypeNormal, "PSIDataRestored", "PSI data is available for all monitored resources")
+		return
+	}
+	c.recordEvent(ctx, nodeName, corev1.EventTypeWarning, "PSIDataUnavailable",
+		fmt.Sprintf("PSI data %s for %s (missingData policy: %s)", reason, strings.Join(unavailable, ", "), c.config.MissingData.Policy))
+}
+
+func (c *Controller) recordEvent(ctx context.Context, nodeName, eventType, reason, message string) {
+	if err := c.kubeClient.RecordNodeEvent(ctx, nodeName, eventType, reason, message); err != nil {
+		c.logger.Error("Error recording event", logging.Node(nodeName), slog.String("reason", reason), logging.Err(err))
+	}
+}
+
+// withoutResources returns a copy of nodePSI with the given resources zeroed
+// and marked missing, so that they cannot contribute to a threshold breach
+// and smoothing skips them.
+func withoutResources(nodePSI *psi.NodePSI, resources []string) *psi.NodePSI {
+	sample := *nodePSI
+	sample.Missing = slices.Clone(nodePSI.Missing)
+	for _, r := range resources {
+		if p := sample.ForResource(r); p != nil {
+			*p = psi.Pressure{}
+		}
+		if !sample.IsMissing(r) {
+			sample.Missing = append(sample.Missing, r)
+		}
+	}
+	return &sample
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// breach describes a single threshold exceeded on a node. PSI breaches set
+// resource, kind and window; matched rules set rule; other signals set
+// signal instead.
+type breach struct {
+	resource  string
+	kind      string
+	window    string
+	signal    string
+	rule      string
+	value     float64
+	threshold float64
+	// below is set for signals that breach when the value drops under the
+	// threshold; condition for node conditions that are True.
+	below     bool
+	condition bool
+}
+
+// name returns the dotted name of the breached signal, e.g. "cpu.some.avg10".
+func (b breach) name() string {
+	if b.rule != "" {
+		return "rule." + b.rule
+	}
+	if b.signal != "" {
+		return b.signal
+	}
+	return b.resource + "." + b.kind + "." + b.window
+}
+
+// severity returns how far the breach exceeds its threshold: value /
+// threshold, or threshold / value for signals that breach below their
+// threshold, so that thresholds, trends and outliers compare alike.
+// Breaches without a threshold, such as rules and node conditions, count
+// as 1.
+func (b breach) severity() float64 {
+	switch {
+	case b.threshold <= 0:
+		return 1
+	case b.below && b.value <= 0:
+		return math.Inf(1)
+	case b.below:
+		return b.threshold / b.value
+	default:
+		return b.value / b.threshold
+	}
 }
 
-func (c *Controller) checkAverages(actual psi.Averages, threshold config.PSIAverages, nodeName, label string) bool {
-	exceeded := false
+// attrs returns the log attributes of the breach.
+func (b breach) attrs() []any {
+	switch {
+	case b.rule != "":
+		return []any{slog.String("rule", b.rule)}
+	case b.resource != "":
+		return logging.Threshold(b.resource, b.kind, b.window, b.value, b.threshold)
+	default:
+		return append([]any{slog.String("signal", b.signal)}, logging.Threshold(breachResource(b), "", "", b.value, b.threshold)...)
+	}
+}
+
+func (b breach) String() string {
+	switch {
+	case b.rule != "":
+		return "rule " + b.rule
+	case b.condition:
+		return b.name() + "=True"
+	case b.below:
+		return fmt.Sprintf("%s %.0f < %.0f", b.name(), b.value, b.threshold)
+	default:
+		return fmt.Sprintf("%s %.2f > %.2f", b.name(), b.value, b.threshold)
+	}
+}
+
+func (c *Controller) isThresholdExceeded(nodePSI *psi.NodePSI, nodeName string) bool {
+	return len(c.evaluate(nodePSI, nil, nodeName)) > 0
+}
 
-	if threshold.Avg10 > 0 && actual.Avg10 > threshold.Avg10 {
-		c.logger.Printf("Node %s: %s.avg10 (%.2f) exceeded threshold (%.2f)", nodeName, label, actual.Avg10, threshold.Avg10)
-		exceeded = true
+// evaluate compares every configured threshold, signal, trend and rule
+// against the sample and the node's state, and returns the ones that were
+// exceeded. state may be nil, in which case conditions and history are
+// treated as empty.
+func (c *Controller) evaluate(nodePSI *psi.NodePSI, state *nodeState, nodeName string) []breach {
+	if state == nil {
+		state = &nodeState{}
 	}
-	if threshold.Avg60 > 0 && actual.Avg60 > threshold.Avg60 {
-		c.logger.Printf("Node %s: %s.avg60 (%.2f) exceeded threshold (%.2f)", nodeName, label, actual.Avg60, threshold.Avg60)
-		exceeded = true
+	t := c.thresholds()
+	breaches := c.evaluateSignals(nodePSI, state.conditions, nodeName)
+	breaches = append(breaches, c.evaluateTrends(nodePSI, state.history, nodeName)...)
+	breaches = c.checkAverages(breaches, nodePSI.CPU.Some, t.CPU.Some, nodeName, psi.ResourceCPU, "some")
+	breaches = c.checkAverages(breaches, nodePSI.CPU.Full, t.CPU.Full, nodeName, psi.ResourceCPU, "full")
+	breaches = c.checkAverages(breaches, nodePSI.Memory.Some, t.Memory.Some, nodeName, psi.ResourceMemory, "some")
+	breaches = c.checkAverages(breaches, nodePSI.Memory.Full, t.Memory.Full, nodeName, psi.ResourceMemory, "full")
+	breaches = c.checkAverages(breaches, nodePSI.IO.Some, t.IO.Some, nodeName, psi.ResourceIO, "some")
+	breaches = c.checkAverages(breaches, nodePSI.IO.Full, t.IO.Full, nodeName, psi.ResourceIO, "full")
+	breaches = c.evaluateRules(breaches, nodePSI, nodeName)
+	return breaches
+}
+
+func (c *Controller) checkAverages(breaches []breach, actual psi.Averages, threshold config.PSIAverages, nodeName, resource, kind string) []breach {
+	windows := []struct {
+		name      string
+		value     float64
+		threshold float64
+	}{
+		{"avg10", actual.Avg10, threshold.Avg10},
+		{"avg60", actual.Avg60, threshold.Avg60},
+		{"avg300", actual.Avg300, threshold.Avg300},
 	}
-	if threshold.Avg300 > 0 && actual.Avg300 > threshold.Avg300 {
-		c.logger.Printf("Node %s: %s.avg300 (%.2f) exceeded threshold (%.2f)", nodeName, label, actual.Avg300, threshold.Avg300)
-		exceeded = true
+
+	for _, w := range windows {
+		if w.threshold <= 0 {
+			continue
+		}
+		exceeded := w.value > w.threshold
+		metrics.ThresholdExceeded.WithLabelValues(nodeName, resource, kind, w.name).Set(boolToFloat(exceeded))
+		if !exceeded {
+			continue
+		}
+		b := breach{resource: resource, kind: kind, window: w.name, value: w.value, threshold: w.threshold}
+		c.logBreach(nodeName, b)
+		breaches = append(breaches, b)
 	}
 
-	return exceeded
+	return breaches
 }
 
 func (c *Controller) handleExceeded(ctx context.Context, nodeName string, state *nodeState) {
+	acts := c.actuatorsFor(state.node)
+	node := target(nodeName, state)
 	if state.tainted {
-		state.lastTaintTime = time.Now()
+		state.lastTaintTime = c.now()
+		c.refreshActions(ctx, state.applied, node, c.decision(state, c.taintedNodes()))
+		state.action = Action{Actions: describeActions(state.applied), Result: ResultRefreshed}
+		c.reportTopPods(ctx, nodeName, state)
+		c.maybeEvict(ctx, nodeName, state)
 		return
 	}
 
-	c.logger.Printf("Threshold exceeded on node %s. Applying taint %s=%s:%s",
-		nodeName, c.config.TaintKey, "high-load", c.config.TaintEffect)
-	err := c.kubeClient.ApplyTaint(ctx, nodeName, c.config.TaintKey, "high-load", c.config.TaintEffect)
+	actions := describeActions(acts)
+	d := c.decision(state, c.taintedNodes()+1)
+	c.logger.Info("Threshold exceeded, applying actions", logging.Node(nodeName), logging.Action(actions), logging.Resource(d.Resource))
+	// After a partial failure only the actions not in place yet are retried.
+	pending := slices.DeleteFunc(slices.Clone(acts), func(a actuator.Actuator) bool { return slices.Contains(state.applied, a) })
+	applied, err := actuatePressure(ctx, pending, node, d)
+	state.applied = mergeActuators(state.applied, applied)
 	if err != nil {
-		c.logger.Printf("Error applying taint to node %s: %v", nodeName, err)
-	} else {
-		state.tainted = true
-		state.lastTaintTime = time.Now()
-		c.logger.Printf("Taint %s applied to node %s.", c.config.TaintKey, nodeName)
+		c.logger.Error("Error applying actions", logging.Node(nodeName), logging.Action(actions), logging.Err(err))
+		state.action = Action{Actions: actions, Result: ResultFailed, Error: err.Error()}
+		return
+	}
+	state.tainted = true
+	state.lastTaintTime = c.now()
+	state.taintedSince = state.lastTaintTime
+	c.logger.Info("Applied actions", logging.Node(nodeName), logging.Action(actions))
+	state.action = Action{Actions: actions, Result: ResultApplied}
+	c.emitTainted(nodeName, actions, d)
+	c.recordTaintCycle(nodeName, state)
+	c.recordReason(ctx, nodeName, d)
+	c.disableScaleDown(ctx, nodeName, state)
+	message := fmt.Sprintf("Applied %s: %s%s", actions, describeBreaches(state), c.scheduleSuffix())
+	if topPods := c.reportTopPods(ctx, nodeName, state); topPods != "" {
+		message += "; top pods: " + topPods
 	}
+	c.recordEvent(ctx, nodeName, corev1.EventTypeWarning, eventReason(acts, "Applied"), message)
 }
 
 func (c *Controller) handleNotExceeded(ctx context.Context, nodeName string, state *nodeState) {
 	if !state.tainted {
+		if len(state.applied) > 0 {
+			// Pressure subsided before every action could be applied.
+			c.release(ctx, nodeName, state, "after pressure subsided")
+		}
 		return
 	}
 
-	if time.Since(state.lastTaintTime) >= c.config.CooldownPeriod {
-		c.logger.Printf("All metrics below thresholds on node %s and cooldown passed. Removing taint %s",
-			nodeName, c.config.TaintKey)
-		err := c.kubeClient.RemoveTaint(ctx, nodeName, c.config.TaintKey, c.config.TaintEffect)
-		if err != nil {
-			c.logger.Printf("Error removing taint from node %s: %v", nodeName, err)
-		} else {
-			state.tainted = false
-			c.logger.Printf("Taint %s removed from node %s.", c.config.TaintKey, nodeName)
-		}
+	if cond := c.holdingCondition(state); cond != "" {
+		c.logger.Info("Holding condition is True, keeping actions", logging.Node(nodeName), slog.String("condition", cond))
+		return
+	}
+
+	if c.now().Sub(state.lastTaintTime) >= c.effectiveCooldown(state) {
+		c.logger.Info("All metrics below thresholds and cooldown passed, releasing actions", logging.Node(nodeName),
+			logging.Action(describeActions(state.applied)))
+		c.release(ctx, nodeName, state, "after pressure subsided")
+	}
+}
+
+// release reverts the pressure actions applied to the node and records
+// why, e.g. "after pressure subsided". Actions that fail to revert are
+// retried on the next poll.
+func (c *Controller) release(ctx context.Context, nodeName string, state *nodeState, reason string) {
+	acts := state.applied
+	actions := describeActions(acts)
+	d := actuator.Decision{Time: c.now(), Reason: reason, TaintedNodes: c.taintedNodes()}
+	if state.tainted {
+		d.TaintedNodes--
+	}
+	remaining, err := actuateRecovered(ctx, acts, target(nodeName, state), d)
+	state.applied = remaining
+	if err != nil {
+		c.logger.Error("Error releasing actions", logging.Node(nodeName), logging.Action(actions), logging.Err(err))
+		state.action = Action{Actions: actions, Result: ResultFailed, Error: err.Error()}
+		return
+	}
+	if !state.tainted {
+		c.logger.Info("Reverted partially applied actions", logging.Node(nodeName), logging.Action(actions), slog.String("reason", reason))
+		state.action = Action{Actions: actions, Result: ResultReleased}
+		return
 	}
+	state.tainted = false
+	state.untaintedAt = c.now()
+	c.logger.Info("Released actions", logging.Node(nodeName), logging.Action(actions), slog.String("reason", reason))
+	state.action = Action{Actions: actions, Result: ResultReleased}
+	c.emitUntainted(nodeName, actions, state, d)
+	c.clearTopPods(ctx, nodeName, state)
+	c.clearReason(ctx, nodeName)
+	c.restoreScaleDown(ctx, nodeName, state)
+	c.recordEvent(ctx, nodeName, corev1.EventTypeNormal, eventReason(acts, "Removed"),
+		fmt.Sprintf("Removed %s %s", actions, reason))
 }
 
 // WatchSignals sets up a listener for OS signals to gracefully shut down.
-func WatchSignals(cancel context.CancelFunc, logger *log.Logger) {
+func WatchSignals(cancel context.CancelFunc, logger *slog.Logger) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		sig := <-sigChan
-		logger.Printf("Received signal: %s. Initiating shutdown...", sig)
+		logger.Info("Received signal, initiating shutdown", slog.String("signal", sig.String()))
 		cancel()
 	}()
 }
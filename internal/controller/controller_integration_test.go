@@ -3,7 +3,7 @@ package controller
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"os"
 	"testing"
 	"time"
@@ -30,7 +30,7 @@ func (m *mockPSIFetcher) FetchNodePSI(_ context.Context, nodeName string) (*psi.
 }
 
 func TestController_PollAllNodes_AppliesTaintOnHighLoad(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1", "node-2"})
@@ -53,7 +53,7 @@ func TestController_PollAllNodes_AppliesTaintOnHighLoad(t *testing.T) {
 }
 
 func TestController_PollAllNodes_RemovesStaleNodeState(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1"})
@@ -78,7 +78,7 @@ func TestController_PollAllNodes_RemovesStaleNodeState(t *testing.T) {
 }
 
 func TestController_PollAllNodes_ListNodesError(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient(nil)
@@ -86,7 +86,7 @@ func TestController_PollAllNodes_ListNodesError(t *testing.T) {
 	mockPSI := &mockPSIFetcher{}
 
 	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
-	ctrl.nodes["node-1"] = &nodeState{tainted: true}
+	ctrl.nodes["node-1"] = &nodeState{tainted: true, applied: ctrl.actuators}
 
 	ctrl.pollAllNodes(context.Background())
 
@@ -95,8 +95,8 @@ func TestController_PollAllNodes_ListNodesError(t *testing.T) {
 	}
 }
 
-func TestController_CheckNode_PSIFetchError(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+func TestController_Poll_PSIFetchError(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1"})
@@ -105,15 +105,15 @@ func TestController_CheckNode_PSIFetchError(t *testing.T) {
 	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
 	ctrl.nodes["node-1"] = &nodeState{tainted: false}
 
-	ctrl.checkNode(context.Background(), "node-1")
+	ctrl.pollAllNodes(context.Background())
 
 	if mockKube.getApplyCalls() != 0 {
 		t.Error("Expected no taint changes when PSI fetch fails")
 	}
 }
 
-func TestController_CheckNode_DetectsExistingTaint(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+func TestController_Poll_DetectsExistingTaint(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1"})
@@ -126,7 +126,7 @@ func TestController_CheckNode_DetectsExistingTaint(t *testing.T) {
 
 	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
 
-	ctrl.checkNode(context.Background(), "node-1")
+	ctrl.pollAllNodes(context.Background())
 
 	state := ctrl.nodes["node-1"]
 	if state == nil {
@@ -137,8 +137,8 @@ func TestController_CheckNode_DetectsExistingTaint(t *testing.T) {
 	}
 }
 
-func TestController_CheckNode_HasTaintError(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+func TestController_Poll_HasTaintError(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1"})
@@ -151,7 +151,7 @@ func TestController_CheckNode_HasTaintError(t *testing.T) {
 
 	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
 
-	ctrl.checkNode(context.Background(), "node-1")
+	ctrl.pollAllNodes(context.Background())
 
 	if _, exists := ctrl.nodes["node-1"]; exists {
 		t.Error("Expected node state not to be created on HasTaint error")
@@ -159,14 +159,14 @@ func TestController_CheckNode_HasTaintError(t *testing.T) {
 }
 
 func TestController_HandleExceeded_AlreadyTainted_ResetsCooldown(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1"})
 
 	ctrl := NewController(cfg, mockKube, psi.NewFetcher(nil), logger)
 	oldTime := time.Now().Add(-10 * time.Minute)
-	ctrl.nodes["node-1"] = &nodeState{tainted: true, lastTaintTime: oldTime}
+	ctrl.nodes["node-1"] = &nodeState{tainted: true, applied: ctrl.actuators, lastTaintTime: oldTime}
 
 	ctrl.handleExceeded(context.Background(), "node-1", ctrl.nodes["node-1"])
 
@@ -179,7 +179,7 @@ func TestController_HandleExceeded_AlreadyTainted_ResetsCooldown(t *testing.T) {
 }
 
 func TestController_HandleExceeded_ApplyError(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1"})
@@ -196,7 +196,7 @@ func TestController_HandleExceeded_ApplyError(t *testing.T) {
 }
 
 func TestController_HandleNotExceeded_RemoveError(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 	cfg.CooldownPeriod = 1 * time.Millisecond
 
@@ -204,7 +204,7 @@ func TestController_HandleNotExceeded_RemoveError(t *testing.T) {
 	mockKube.removeTaintErr = errors.New("conflict")
 
 	ctrl := NewController(cfg, mockKube, psi.NewFetcher(nil), logger)
-	ctrl.nodes["node-1"] = &nodeState{tainted: true, lastTaintTime: time.Now().Add(-1 * time.Minute)}
+	ctrl.nodes["node-1"] = &nodeState{tainted: true, applied: ctrl.actuators, lastTaintTime: time.Now().Add(-1 * time.Minute)}
 
 	ctrl.handleNotExceeded(context.Background(), "node-1", ctrl.nodes["node-1"])
 
@@ -214,7 +214,7 @@ func TestController_HandleNotExceeded_RemoveError(t *testing.T) {
 }
 
 func TestController_Run_ContextCancellation(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 	cfg.PollInterval = 10 * time.Millisecond
 
@@ -246,7 +246,7 @@ func TestController_Run_ContextCancellation(t *testing.T) {
 
 // newControllerWithMockPSI creates a Controller with a mock PSI fetcher
 // by embedding it in the struct. This requires the psiFetchFunc field.
-func newControllerWithMockPSI(cfg *config.Config, kubeClient *mockKubeClient, mockPSI *mockPSIFetcher, logger *log.Logger) *Controller {
+func newControllerWithMockPSI(cfg *config.Config, kubeClient *mockKubeClient, mockPSI *mockPSIFetcher, logger *slog.Logger) *Controller {
 	ctrl := NewController(cfg, kubeClient, psi.NewFetcher(nil), logger)
 	ctrl.psiFetchFunc = mockPSI.FetchNodePSI
 	return ctrl
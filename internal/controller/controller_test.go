@@ -2,15 +2,28 @@ package controller
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/Fedosin/kube-dethrottler/internal/actuator"
 	"github.com/Fedosin/kube-dethrottler/internal/config"
 	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+	"github.com/Fedosin/kube-dethrottler/internal/notify"
 	"github.com/Fedosin/kube-dethrottler/internal/psi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -21,25 +34,47 @@ type mockKubeClient struct {
 	listNodesErr   error
 	taints         map[string]corev1.Taint
 	nodeNames      []string
+	// nodes holds the other fields of listed nodes, e.g. lifecycle flags.
+	nodes          map[string]kubernetes.NodeInfo
+	conditions     map[string]map[string]bool
+	labels         map[string]map[string]string
+	nodeConditions map[string]string
+	events         []string
+	annotations    map[string]string
+	podLabels      map[string]map[string]string
+	evictErr       error
+	evicted        []string
 	mu             sync.Mutex
 	applyCalls     int
 	removeCalls    int
+	evictCalls     int
+	// annotationPatches counts PatchNodeAnnotations calls.
+	annotationPatches int
+	// configMaps maps "namespace/name/key" to a ConfigMap value.
+	configMaps map[string]string
 }
 
 func newMockKubeClient(nodes []string) *mockKubeClient {
 	return &mockKubeClient{
-		nodeNames: nodes,
-		taints:    make(map[string]corev1.Taint),
+		nodeNames:   nodes,
+		taints:      make(map[string]corev1.Taint),
+		annotations: make(map[string]string),
 	}
 }
 
-func (m *mockKubeClient) ListNodeNames(_ context.Context, _ string) ([]string, error) {
+func (m *mockKubeClient) ListNodes(_ context.Context, _ string) ([]kubernetes.NodeInfo, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.listNodesErr != nil {
 		return nil, m.listNodesErr
 	}
-	return m.nodeNames, nil
+	nodes := make([]kubernetes.NodeInfo, 0, len(m.nodeNames))
+	for _, name := range m.nodeNames {
+		node := m.nodes[name]
+		node.Name, node.Conditions, node.Labels = name, m.conditions[name], m.labels[name]
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
 }
 
 func (m *mockKubeClient) HasTaint(_ context.Context, nodeName, taintKey, taintEffect string) (bool, error) {
@@ -77,6 +112,133 @@ func (m *mockKubeClient) RemoveTaint(_ context.Context, nodeName, taintKey, tain
 	return nil
 }
 
+func (m *mockKubeClient) RecordNodeEvent(_ context.Context, nodeName, _, reason, _ string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, nodeName+"/"+reason)
+	return nil
+}
+
+func (m *mockKubeClient) SetNodeAnnotation(_ context.Context, nodeName, key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.annotations[nodeName+"/"+key] = value
+	return nil
+}
+
+func (m *mockKubeClient) RemoveNodeAnnotation(_ context.Context, nodeName, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.annotations, nodeName+"/"+key)
+	return nil
+}
+
+func (m *mockKubeClient) PatchNodeAnnotations(_ context.Context, nodeName string, annotations map[string]*string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.annotationPatches++
+	for key, value := range annotations {
+		if value == nil {
+			delete(m.annotations, nodeName+"/"+key)
+		} else {
+			m.annotations[nodeName+"/"+key] = *value
+		}
+	}
+	return nil
+}
+
+func (m *mockKubeClient) GetPodLabels(_ context.Context, namespace, name string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.podLabels[namespace+"/"+name], nil
+}
+
+func (m *mockKubeClient) EvictPod(_ context.Context, namespace, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evictCalls++
+	if m.evictErr != nil {
+		return m.evictErr
+	}
+	m.evicted = append(m.evicted, namespace+"/"+name)
+	return nil
+}
+
+func (m *mockKubeClient) SetNodeCondition(_ context.Context, nodeName, conditionType string, status bool, reason, _ string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.nodeConditions == nil {
+		m.nodeConditions = make(map[string]string)
+	}
+	m.nodeConditions[nodeName+"/"+conditionType] = fmt.Sprintf("%v/%s", status, reason)
+	return nil
+}
+
+func (m *mockKubeClient) SetNodeLabel(_ context.Context, nodeName, key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.labels == nil {
+		m.labels = make(map[string]map[string]string)
+	}
+	if m.labels[nodeName] == nil {
+		m.labels[nodeName] = make(map[string]string)
+	}
+	m.labels[nodeName][key] = value
+	return nil
+}
+
+func (m *mockKubeClient) GetConfigMapValue(_ context.Context, namespace, name, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.configMaps[namespace+"/"+name+"/"+key], nil
+}
+
+func (m *mockKubeClient) setConfigMapValue(namespace, name, key, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.configMaps == nil {
+		m.configMaps = make(map[string]string)
+	}
+	m.configMaps[namespace+"/"+name+"/"+key] = value
+}
+
+func (m *mockKubeClient) setLabel(nodeName, key, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.labels[nodeName][key] = value
+}
+
+func (m *mockKubeClient) getLabel(nodeName, key string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.labels[nodeName][key]
+}
+
+func (m *mockKubeClient) getNodeCondition(nodeName, conditionType string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nodeConditions[nodeName+"/"+conditionType]
+}
+
+func (m *mockKubeClient) getEvicted() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.evicted...)
+}
+
+func (m *mockKubeClient) getAnnotation(nodeName, key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.annotations[nodeName+"/"+key]
+	return v, ok
+}
+
+func (m *mockKubeClient) getEvents() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.events...)
+}
+
 func (m *mockKubeClient) getApplyCalls() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -114,7 +276,7 @@ func testConfig() *config.Config {
 }
 
 func TestController_ApplyTaint_WhenThresholdExceeded(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1"})
@@ -146,7 +308,7 @@ func TestController_ApplyTaint_WhenThresholdExceeded(t *testing.T) {
 }
 
 func TestController_NoTaint_WhenBelowThreshold(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1"})
@@ -172,7 +334,7 @@ func TestController_NoTaint_WhenBelowThreshold(t *testing.T) {
 }
 
 func TestController_RemoveTaint_AfterCooldown(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 	cfg.CooldownPeriod = 1 * time.Millisecond
 
@@ -182,6 +344,7 @@ func TestController_RemoveTaint_AfterCooldown(t *testing.T) {
 	ctrl := NewController(cfg, mockKube, psiFetcher, logger)
 	ctrl.nodes["node-1"] = &nodeState{
 		tainted:       true,
+		applied:       ctrl.actuators,
 		lastTaintTime: time.Now().Add(-1 * time.Minute),
 	}
 
@@ -196,7 +359,7 @@ func TestController_RemoveTaint_AfterCooldown(t *testing.T) {
 }
 
 func TestController_NoRemove_DuringCooldown(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 	cfg.CooldownPeriod = 5 * time.Minute
 
@@ -206,6 +369,7 @@ func TestController_NoRemove_DuringCooldown(t *testing.T) {
 	ctrl := NewController(cfg, mockKube, psiFetcher, logger)
 	ctrl.nodes["node-1"] = &nodeState{
 		tainted:       true,
+		applied:       ctrl.actuators,
 		lastTaintTime: time.Now(),
 	}
 
@@ -220,7 +384,7 @@ func TestController_NoRemove_DuringCooldown(t *testing.T) {
 }
 
 func TestController_Shutdown_RemovesTaints(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 
 	mockKube := newMockKubeClient([]string{"node-1", "node-2"})
@@ -229,7 +393,7 @@ func TestController_Shutdown_RemovesTaints(t *testing.T) {
 	psiFetcher := psi.NewFetcher(nil)
 
 	ctrl := NewController(cfg, mockKube, psiFetcher, logger)
-	ctrl.nodes["node-1"] = &nodeState{tainted: true, lastTaintTime: time.Now()}
+	ctrl.nodes["node-1"] = &nodeState{tainted: true, applied: ctrl.actuators, lastTaintTime: time.Now()}
 	ctrl.nodes["node-2"] = &nodeState{tainted: false}
 
 	ctrl.cleanupTaints()
@@ -243,7 +407,7 @@ func TestController_Shutdown_RemovesTaints(t *testing.T) {
 }
 
 func TestController_MultipleThresholds(t *testing.T) {
-	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := testConfig()
 	cfg.Thresholds = config.PSIThresholds{
 		CPU: config.PSIPressure{
@@ -273,3 +437,1371 @@ func TestController_MultipleThresholds(t *testing.T) {
 		t.Error("Expected threshold to be exceeded (memory.full.avg10 > 5.0)")
 	}
 }
+
+func TestController_MissingData_Policies(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	missingCPU := &psi.NodePSI{Missing: []string{psi.ResourceCPU}}
+
+	tests := []struct {
+		name        string
+		policy      string
+		wantEvents  []string
+		wantTainted bool
+		wantUnknown bool
+	}{
+		{name: "ignore", policy: config.MissingDataIgnore, wantTainted: false,
+			wantEvents: []string{"node-1/PSIDataUnavailable"}},
+		{name: "breach", policy: config.MissingDataBreach, wantTainted: true,
+			wantEvents: []string{"node-1/PSIDataUnavailable", "node-1/PressureTaintApplied"}},
+		{name: "unknown", policy: config.MissingDataUnknown, wantTainted: false, wantUnknown: true,
+			wantEvents: []string{"node-1/PSIDataUnavailable"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.MissingData.Policy = tt.policy
+
+			mockKube := newMockKubeClient([]string{"node-1"})
+			mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": missingCPU}}
+			ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+			ctrl.pollAllNodes(context.Background())
+
+			if got := mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect); got != tt.wantTainted {
+				t.Errorf("tainted = %v, want %v", got, tt.wantTainted)
+			}
+			if got := ctrl.nodes["node-1"].unknown; got != tt.wantUnknown {
+				t.Errorf("unknown = %v, want %v", got, tt.wantUnknown)
+			}
+			if events := mockKube.getEvents(); !slices.Equal(events, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", events, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestController_MissingData_UnknownKeepsTaint(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = time.Millisecond
+	cfg.MissingData.Policy = config.MissingDataUnknown
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"node-1": {Missing: []string{psi.ResourceCPU, psi.ResourceMemory, psi.ResourceIO}},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	ctrl.nodes["node-1"] = &nodeState{tainted: true, applied: ctrl.actuators, lastTaintTime: time.Now().Add(-time.Hour)}
+
+	ctrl.pollAllNodes(context.Background())
+
+	if mockKube.getRemoveCalls() != 0 {
+		t.Error("Expected taint to be left in place while node state is unknown")
+	}
+}
+
+func TestController_MissingData_StaleSample(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.MissingData = config.MissingData{Policy: config.MissingDataIgnore, MaxSampleAge: time.Minute}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"node-1": {
+			Timestamp: time.Now().Add(-10 * time.Minute),
+			CPU:       psi.Pressure{Some: psi.Averages{Avg10: 90.0}},
+		},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	if mockKube.getApplyCalls() != 0 {
+		t.Error("Expected stale sample to be ignored")
+	}
+	if got := ctrl.nodes["node-1"].unavailable; len(got) != 1 || got[0] != psi.ResourceCPU {
+		t.Errorf("Expected cpu to be reported unavailable, got %v", got)
+	}
+}
+
+func TestController_TopPods_ReportedOnTaint(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.PodAnalysis = config.PodAnalysis{Enabled: true, TopN: 2, AnnotationKey: "kube-dethrottler.io/top-pods"}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"node-1": {
+			CPU: psi.Pressure{Some: psi.Averages{Avg10: 60.0}},
+			Pods: []psi.PodPSI{
+				{Namespace: "default", Name: "quiet", CPU: psi.Pressure{Some: psi.Averages{Avg10: 1.0}}},
+				{Namespace: "batch", Name: "noisy", CPU: psi.Pressure{Some: psi.Averages{Avg10: 55.0}}},
+				{Namespace: "default", Name: "busy", CPU: psi.Pressure{Some: psi.Averages{Avg10: 20.0}}},
+				{Namespace: "default", Name: "idle", Memory: psi.Pressure{Some: psi.Averages{Avg10: 90.0}}},
+			},
+		},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	got, ok := mockKube.getAnnotation("node-1", cfg.PodAnalysis.AnnotationKey)
+	if !ok {
+		t.Fatal("Expected top pods annotation to be set")
+	}
+	if want := "batch/noisy(cpu=55.00), default/busy(cpu=20.00)"; got != want {
+		t.Errorf("annotation = %q, want %q", got, want)
+	}
+	if events := mockKube.getEvents(); len(events) != 1 || events[0] != "node-1/PressureTaintApplied" {
+		t.Errorf("Expected a PressureTaintApplied event, got %v", events)
+	}
+
+	// Pressure subsides: the annotation is removed along with the taint.
+	cfg.CooldownPeriod = 0
+	mockPSI.results["node-1"] = &psi.NodePSI{}
+	ctrl.pollAllNodes(context.Background())
+
+	if _, ok := mockKube.getAnnotation("node-1", cfg.PodAnalysis.AnnotationKey); ok {
+		t.Error("Expected top pods annotation to be removed after untaint")
+	}
+}
+
+func TestController_Eviction(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Eviction = config.Eviction{
+		Enabled:           true,
+		Namespaces:        []string{"batch"},
+		PodSelector:       "evictable=true",
+		CriticalFullAvg10: 20,
+		TaintedFor:        time.Minute,
+		NodeInterval:      time.Hour,
+		ClusterInterval:   time.Hour,
+	}
+
+	mockKube := newMockKubeClient([]string{"node-1", "node-2"})
+	mockKube.podLabels = map[string]map[string]string{
+		"batch/protected": {"evictable": "false"},
+		"batch/noisy":     {"evictable": "true"},
+	}
+	sample := &psi.NodePSI{
+		CPU: psi.Pressure{Some: psi.Averages{Avg10: 80.0}, Full: psi.Averages{Avg10: 30.0}},
+		Pods: []psi.PodPSI{
+			{Namespace: "kube-system", Name: "system", CPU: psi.Pressure{Some: psi.Averages{Avg10: 70.0}}},
+			{Namespace: "batch", Name: "protected", CPU: psi.Pressure{Some: psi.Averages{Avg10: 60.0}}},
+			{Namespace: "batch", Name: "noisy", CPU: psi.Pressure{Some: psi.Averages{Avg10: 50.0}}},
+		},
+	}
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": sample, "node-2": sample}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	// Freshly tainted: too early to evict.
+	ctrl.pollAllNodes(context.Background())
+	if evicted := mockKube.getEvicted(); len(evicted) != 0 {
+		t.Fatalf("Expected no eviction right after tainting, got %v", evicted)
+	}
+
+	ctrl.nodes["node-1"].taintedSince = time.Now().Add(-2 * time.Minute)
+	ctrl.pollAllNodes(context.Background())
+	if evicted := mockKube.getEvicted(); len(evicted) != 1 || evicted[0] != "batch/noisy" {
+		t.Fatalf("Expected batch/noisy to be evicted, got %v", evicted)
+	}
+
+	// The cluster-wide rate limit prevents a second eviction on another node.
+	ctrl.nodes["node-2"].taintedSince = time.Now().Add(-time.Hour)
+	ctrl.pollAllNodes(context.Background())
+	if evicted := mockKube.getEvicted(); len(evicted) != 1 {
+		t.Errorf("Expected cluster-wide rate limit to prevent eviction, got %v", evicted)
+	}
+}
+
+func TestController_Eviction_Blocked(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Eviction = config.Eviction{Enabled: true, Namespaces: []string{"batch"}, CriticalFullAvg10: 20, NodeInterval: 10 * time.Minute}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockKube.evictErr = kubernetes.ErrEvictionBlocked
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": {
+		CPU:  psi.Pressure{Some: psi.Averages{Avg10: 80.0}, Full: psi.Averages{Avg10: 30.0}},
+		Pods: []psi.PodPSI{{Namespace: "batch", Name: "noisy", CPU: psi.Pressure{Some: psi.Averages{Avg10: 50.0}}}},
+	}}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	ctrl.now = func() time.Time { return now }
+	ctrl.nodes["node-1"] = &nodeState{tainted: true, applied: ctrl.actuators, taintedSince: now.Add(-time.Hour), lastTaintTime: now}
+
+	// A blocked eviction is not retried before the next per-node window.
+	for range 3 {
+		ctrl.pollAllNodes(context.Background())
+		now = now.Add(time.Minute)
+	}
+	if mockKube.evictCalls != 1 {
+		t.Fatalf("EvictPod calls = %d, want 1 within the window", mockKube.evictCalls)
+	}
+
+	now = now.Add(10 * time.Minute)
+	ctrl.pollAllNodes(context.Background())
+	if mockKube.evictCalls != 2 {
+		t.Errorf("EvictPod calls = %d, want a retry after the window", mockKube.evictCalls)
+	}
+}
+
+func TestController_Eviction_BelowCriticalLevel(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Eviction = config.Eviction{Enabled: true, Namespaces: []string{"batch"}, CriticalFullAvg10: 50}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": {
+		CPU:  psi.Pressure{Some: psi.Averages{Avg10: 80.0}, Full: psi.Averages{Avg10: 10.0}},
+		Pods: []psi.PodPSI{{Namespace: "batch", Name: "noisy", CPU: psi.Pressure{Some: psi.Averages{Avg10: 50.0}}}},
+	}}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	ctrl.nodes["node-1"] = &nodeState{tainted: true, applied: ctrl.actuators, taintedSince: time.Now().Add(-time.Hour), lastTaintTime: time.Now()}
+
+	ctrl.pollAllNodes(context.Background())
+
+	if evicted := mockKube.getEvicted(); len(evicted) != 0 {
+		t.Errorf("Expected no eviction below the critical level, got %v", evicted)
+	}
+}
+
+func TestController_ExtraSignals(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Thresholds.Signals = config.ExtraSignals{
+		MemoryAvailableBelow:    "1Gi",
+		FSAvailableBelowPercent: 10,
+		TaintOnConditions:       []string{"MemoryPressure"},
+	}
+
+	lowMemory := uint64(512 << 20)
+	plentyMemory := uint64(8 << 30)
+	fsAvailable, fsCapacity := uint64(5), uint64(100)
+
+	mockKube := newMockKubeClient([]string{"low-memory", "low-disk", "condition", "healthy"})
+	mockKube.conditions = map[string]map[string]bool{"condition": {"MemoryPressure": true}}
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"low-memory": {MemoryAvailableBytes: &lowMemory},
+		"low-disk":   {MemoryAvailableBytes: &plentyMemory, FSAvailableBytes: &fsAvailable, FSCapacityBytes: &fsCapacity},
+		"condition":  {MemoryAvailableBytes: &plentyMemory},
+		"healthy":    {MemoryAvailableBytes: &plentyMemory},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	for node, want := range map[string]bool{"low-memory": true, "low-disk": true, "condition": true, "healthy": false} {
+		if got := mockKube.hasTaintForNode(node, cfg.TaintKey, cfg.TaintEffect); got != want {
+			t.Errorf("node %s tainted = %v, want %v", node, got, want)
+		}
+	}
+}
+
+func TestController_HoldOnConditions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = time.Millisecond
+	cfg.Thresholds.Signals.HoldOnConditions = []string{"DiskPressure"}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockKube.conditions = map[string]map[string]bool{"node-1": {"DiskPressure": true}}
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": {}}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	ctrl.nodes["node-1"] = &nodeState{tainted: true, applied: ctrl.actuators, lastTaintTime: time.Now().Add(-time.Hour)}
+
+	ctrl.pollAllNodes(context.Background())
+	if mockKube.getRemoveCalls() != 0 {
+		t.Fatal("Expected taint to be held while DiskPressure is True")
+	}
+
+	mockKube.conditions["node-1"]["DiskPressure"] = false
+	ctrl.pollAllNodes(context.Background())
+	if mockKube.getRemoveCalls() != 1 {
+		t.Error("Expected taint to be removed once DiskPressure cleared")
+	}
+}
+
+func TestController_Rules(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Thresholds.CPU = config.PSIPressure{}
+	cfg.Thresholds.Rules = []config.Rule{
+		{Name: "cpu-sustained", Expression: "cpu.some.avg10 > 40.0 && cpu.some.avg60 > 20.0"},
+		{Name: "memory-stall", Expression: "memory.full.avg10 > 5.0 && !(io.some.avg10 < 2.0)"},
+	}
+
+	mockKube := newMockKubeClient([]string{"sustained", "spike", "memory-io", "memory-only"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"sustained":   {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50, Avg60: 30}}},
+		"spike":       {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50, Avg60: 10}}},
+		"memory-io":   {Memory: psi.Pressure{Full: psi.Averages{Avg10: 8}}, IO: psi.Pressure{Some: psi.Averages{Avg10: 3}}},
+		"memory-only": {Memory: psi.Pressure{Full: psi.Averages{Avg10: 8}}, IO: psi.Pressure{Some: psi.Averages{Avg10: 1}}},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	for node, want := range map[string]bool{"sustained": true, "spike": false, "memory-io": true, "memory-only": false} {
+		if got := mockKube.hasTaintForNode(node, cfg.TaintKey, cfg.TaintEffect); got != want {
+			t.Errorf("node %s tainted = %v, want %v", node, got, want)
+		}
+	}
+	if got := describeBreaches(ctrl.nodes["sustained"]); !strings.Contains(got, "rule cpu-sustained") {
+		t.Errorf("describeBreaches() = %q, want it to name the matched rule", got)
+	}
+}
+
+func TestController_RiseTrigger(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Thresholds.CPU = config.PSIPressure{}
+	cfg.Thresholds.Trends.Rise = []config.RiseTrigger{{Metric: "cpu.some.avg10", RiseBy: 15, Polls: 3}}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	for i, avg10 := range []float64{5, 10, 18, 30} {
+		mockPSI.results["node-1"] = &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: avg10}}}
+		ctrl.pollAllNodes(context.Background())
+
+		// 5 -> 18 rises by 13, 10 -> 30 by 20.
+		want := i == 3
+		if got := mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect); got != want {
+			t.Fatalf("poll %d: tainted = %v, want %v", i, got, want)
+		}
+	}
+	if got := len(ctrl.nodes["node-1"].history); got != 3 {
+		t.Errorf("history length = %d, want 3", got)
+	}
+}
+
+func TestController_DivergenceTrigger(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Thresholds.CPU = config.PSIPressure{}
+	cfg.Thresholds.Trends.Divergence = []config.DivergenceTrigger{
+		{Pressure: "memory.some", Factor: 3, MinAvg10: 10},
+		// Without minAvg10, an idle node has no baseline to diverge from.
+		{Pressure: "cpu.some", Factor: 3},
+	}
+
+	mockKube := newMockKubeClient([]string{"surge", "flat", "low", "idle"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"surge": {Memory: psi.Pressure{Some: psi.Averages{Avg10: 20, Avg300: 4}}},
+		"flat":  {Memory: psi.Pressure{Some: psi.Averages{Avg10: 20, Avg300: 15}}},
+		"low":   {Memory: psi.Pressure{Some: psi.Averages{Avg10: 5, Avg300: 0.5}}},
+		"idle":  {CPU: psi.Pressure{Some: psi.Averages{Avg10: 0.01, Avg300: 0}}},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	for node, want := range map[string]bool{"surge": true, "flat": false, "low": false, "idle": false} {
+		if got := mockKube.hasTaintForNode(node, cfg.TaintKey, cfg.TaintEffect); got != want {
+			t.Errorf("node %s tainted = %v, want %v", node, got, want)
+		}
+	}
+}
+
+func TestController_Outliers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Thresholds.CPU = config.PSIPressure{}
+	cfg.Outliers = config.Outliers{
+		Enabled:   true,
+		PoolLabel: "pool",
+		Method:    config.OutlierMAD,
+		Metrics:   []string{"cpu.some.avg10"},
+		Factor:    3,
+		Floor:     20,
+		Ceiling:   80,
+		MinNodes:  3,
+	}
+
+	cpu := func(v float64) *psi.NodePSI { return &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: v}}} }
+	values := map[string]*psi.NodePSI{
+		"a-1": cpu(10), "a-2": cpu(12), "a-3": cpu(11), "a-4": cpu(40),
+		"b-1": cpu(85), "b-2": cpu(86), "b-3": cpu(84),
+		"c-1": cpu(90), "c-2": cpu(5),
+	}
+	var names []string
+	labels := make(map[string]map[string]string)
+	for name := range values {
+		names = append(names, name)
+		labels[name] = map[string]string{"pool": name[:1]}
+	}
+
+	mockKube := newMockKubeClient(names)
+	mockKube.labels = labels
+	ctrl := newControllerWithMockPSI(cfg, mockKube, &mockPSIFetcher{results: values}, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	// Pool a: median 11.5 + 3*MAD 1 is below the floor, so only a-4 stands out.
+	// Pool b: every node is above the ceiling. Pool c is too small.
+	want := map[string]bool{"a-4": true, "b-1": true, "b-2": true, "b-3": true}
+	for _, node := range names {
+		if got := mockKube.hasTaintForNode(node, cfg.TaintKey, cfg.TaintEffect); got != want[node] {
+			t.Errorf("node %s tainted = %v, want %v", node, got, want[node])
+		}
+	}
+}
+
+func TestController_Outliers_IdlePool(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Thresholds.CPU = config.PSIPressure{}
+	cfg.Outliers = config.Outliers{
+		Enabled:  true,
+		Method:   config.OutlierMAD,
+		Metrics:  []string{"cpu.some.avg10"},
+		Factor:   3,
+		Floor:    10,
+		Ceiling:  100,
+		MinNodes: 3,
+	}
+
+	cpu := func(v float64) *psi.NodePSI { return &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: v}}} }
+	values := map[string]*psi.NodePSI{"node-1": cpu(0), "node-2": cpu(0), "node-3": cpu(0), "node-4": cpu(0.5)}
+	names := slices.Sorted(maps.Keys(values))
+
+	mockKube := newMockKubeClient(names)
+	ctrl := newControllerWithMockPSI(cfg, mockKube, &mockPSIFetcher{results: values}, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	// The median and MAD of an idle pool are 0; the floor keeps node-4 untainted.
+	for _, node := range names {
+		if mockKube.hasTaintForNode(node, cfg.TaintKey, cfg.TaintEffect) {
+			t.Errorf("node %s tainted on an idle pool", node)
+		}
+	}
+}
+
+func TestOutlierBound_Percentile(t *testing.T) {
+	o := config.Outliers{Method: config.OutlierPercentile, Percentile: 90, Factor: 1.5, Ceiling: 100}
+	values := []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+
+	// p90 of 1..10 is 9.1.
+	if got, want := outlierBound(o, values), 13.65; math.Abs(got-want) > 1e-9 {
+		t.Errorf("outlierBound() = %v, want %v", got, want)
+	}
+}
+
+func TestController_Schedules(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Schedules = []config.Schedule{{
+		Name:       "overnight",
+		Start:      "20:00",
+		End:        "06:00",
+		Thresholds: config.ThresholdOverrides{CPU: &config.PSIPressure{Some: config.PSIAverages{Avg10: 60}}},
+	}}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"node-1": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 40}}},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.now = func() time.Time { return time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC) }
+	ctrl.pollAllNodes(context.Background())
+	if ctrl.schedule == nil || ctrl.schedule.Name != "overnight" {
+		t.Fatalf("Expected overnight schedule to be active, got %v", ctrl.schedule)
+	}
+	if mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Fatal("Expected no taint under the relaxed overnight threshold")
+	}
+
+	ctrl.now = func() time.Time { return time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC) }
+	ctrl.pollAllNodes(context.Background())
+	if ctrl.schedule != nil {
+		t.Fatalf("Expected base thresholds during the day, got schedule %s", ctrl.schedule.Name)
+	}
+	if !mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected taint under the base threshold")
+	}
+}
+
+func TestController_CooldownBackoff(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = time.Minute
+	cfg.CooldownBackoff = config.CooldownBackoff{
+		Enabled:     true,
+		Multiplier:  2,
+		MaxCooldown: 5 * time.Minute,
+		QuietPeriod: time.Hour,
+	}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	ctrl := NewController(cfg, mockKube, psi.NewFetcher(nil), logger)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	ctrl.now = func() time.Time { return now }
+	state := &nodeState{}
+	ctrl.nodes["node-1"] = state
+
+	// Flap repeatedly: 1m, 2m, 4m, then capped at 5m.
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute} {
+		ctrl.handleExceeded(context.Background(), "node-1", state)
+		if got := ctrl.effectiveCooldown(state); got != want {
+			t.Fatalf("cycle %d: effective cooldown = %s, want %s", i+1, got, want)
+		}
+		state.lastTaintTime = now.Add(-want)
+		ctrl.handleNotExceeded(context.Background(), "node-1", state)
+		if state.tainted {
+			t.Fatalf("cycle %d: expected taint to be removed after %s", i+1, want)
+		}
+		now = now.Add(10 * time.Minute)
+	}
+
+	// Two quiet hours forget two of the four cycles.
+	now = now.Add(2 * time.Hour)
+	ctrl.handleExceeded(context.Background(), "node-1", state)
+	if got, want := ctrl.effectiveCooldown(state), 4*time.Minute; got != want {
+		t.Errorf("after quiet period: effective cooldown = %s, want %s", got, want)
+	}
+}
+
+func TestController_CooldownBackoff_HoldsTaint(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = time.Minute
+	cfg.CooldownBackoff = config.CooldownBackoff{Enabled: true, Multiplier: 2, MaxCooldown: time.Hour, QuietPeriod: time.Hour}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	ctrl := NewController(cfg, mockKube, psi.NewFetcher(nil), logger)
+	state := &nodeState{tainted: true, applied: ctrl.actuators, cycles: 2, cooldown: 2 * time.Minute, lastTaintTime: time.Now().Add(-90 * time.Second)}
+	ctrl.nodes["node-1"] = state
+
+	ctrl.handleNotExceeded(context.Background(), "node-1", state)
+
+	if mockKube.getRemoveCalls() != 0 {
+		t.Error("Expected the extended cooldown to keep the taint")
+	}
+}
+
+func TestController_Smoothing(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Thresholds.CPU.Some.Avg10 = 60
+	cfg.Smoothing.CPU = 30 * time.Second
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	ctrl.now = func() time.Time { return now }
+
+	// With a poll every half-life, each sample moves the average halfway.
+	for i, tc := range []struct{ raw, smoothed float64 }{{0, 0}, {100, 50}, {100, 75}} {
+		mockPSI.results["node-1"] = &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: tc.raw}}}
+		ctrl.pollAllNodes(context.Background())
+
+		if got := ctrl.nodes["node-1"].sample.CPU.Some.Avg10; math.Abs(got-tc.smoothed) > 1e-9 {
+			t.Fatalf("poll %d: smoothed avg10 = %v, want %v", i, got, tc.smoothed)
+		}
+		wantTainted := tc.smoothed > 60
+		if got := mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect); got != wantTainted {
+			t.Fatalf("poll %d: tainted = %v, want %v", i, got, wantTainted)
+		}
+		now = now.Add(30 * time.Second)
+	}
+}
+
+func TestController_Smoothing_StaleData(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Thresholds.CPU.Some.Avg10 = 60
+	cfg.Smoothing.CPU = 30 * time.Second
+	cfg.MissingData = config.MissingData{Policy: config.MissingDataIgnore, MaxSampleAge: time.Minute}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	ctrl.now = func() time.Time { return now }
+
+	// The stale sample in the middle is ignored rather than averaged as 0.
+	for i, tc := range []struct {
+		age      time.Duration
+		smoothed float64
+	}{{0, 100}, {10 * time.Minute, 100}, {0, 100}} {
+		mockPSI.results["node-1"] = &psi.NodePSI{Timestamp: now.Add(-tc.age), CPU: psi.Pressure{Some: psi.Averages{Avg10: 100}}}
+		ctrl.pollAllNodes(context.Background())
+
+		if got := ctrl.nodes["node-1"].smoothed.CPU.Some.Avg10; math.Abs(got-tc.smoothed) > 1e-9 {
+			t.Fatalf("poll %d: smoothed avg10 = %v, want %v", i, got, tc.smoothed)
+		}
+		if got := testutil.ToFloat64(metrics.PSIPressure.WithLabelValues("node-1", psi.ResourceCPU, "some", "avg10", "raw")); got != 100 {
+			t.Fatalf("poll %d: raw avg10 metric = %v, want the last fresh value", i, got)
+		}
+		now = now.Add(30 * time.Second)
+	}
+	if !mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected node-1 to stay tainted across the stale sample")
+	}
+}
+
+func TestController_NodeLifecycle(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.NodeLifecycle = config.NodeLifecycle{
+		NotReady:      config.LifecycleSkip,
+		Unschedulable: config.LifecycleEvaluate,
+		Deleting:      config.LifecycleUntaint,
+	}
+
+	mockKube := newMockKubeClient([]string{"cordoned", "not-ready", "deleting"})
+	mockKube.nodes = map[string]kubernetes.NodeInfo{
+		"cordoned":  {Unschedulable: true},
+		"not-ready": {NotReady: true},
+		"deleting":  {Deleting: true},
+	}
+	mockKube.taints["deleting/"+cfg.TaintKey+"-"+cfg.TaintEffect] = corev1.Taint{}
+	mockKube.taints["not-ready/"+cfg.TaintKey+"-"+cfg.TaintEffect] = corev1.Taint{}
+	high := &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}}
+	low := &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: 5}}}
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"cordoned": high, "not-ready": low, "deleting": high}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	ctrl.nodes["not-ready"] = &nodeState{tainted: true, applied: ctrl.actuators, lastTaintTime: time.Now().Add(-time.Hour)}
+
+	ctrl.pollAllNodes(context.Background())
+
+	if !mockKube.hasTaintForNode("cordoned", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected cordoned node to be evaluated and tainted")
+	}
+	if !mockKube.hasTaintForNode("not-ready", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected NotReady node to be skipped with its taint untouched")
+	}
+	if mockKube.hasTaintForNode("deleting", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected taint to be removed from the node being deleted")
+	}
+	if ctrl.skipped["not-ready"] != "notReady" || ctrl.skipped["deleting"] != "deleting" {
+		t.Errorf("skipped = %v, want not-ready and deleting", ctrl.skipped)
+	}
+
+	mockKube.nodes = nil
+	ctrl.pollAllNodes(context.Background())
+	if _, skipped := ctrl.skipped["not-ready"]; skipped {
+		t.Error("Expected node to resume evaluation once Ready")
+	}
+	if mockKube.hasTaintForNode("not-ready", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected taint to be removed once the Ready node is evaluated below thresholds")
+	}
+}
+
+func TestController_DisableScaleDown(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = time.Millisecond
+	cfg.Autoscaler.DisableScaleDown = true
+
+	mockKube := newMockKubeClient([]string{"node-1", "pinned"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"node-1": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}},
+		"pinned": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}},
+	}}
+	// "pinned" was annotated by its owner, so the controller must not touch it.
+	mockKube.nodes = map[string]kubernetes.NodeInfo{
+		"pinned": {Annotations: map[string]string{ScaleDownDisabledAnnotation: "true"}},
+	}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	if v, _ := mockKube.getAnnotation("node-1", ScaleDownDisabledAnnotation); v != "true" {
+		t.Errorf("Expected scale-down to be disabled on the tainted node, got %q", v)
+	}
+	if v, _ := mockKube.getAnnotation("node-1", ScaleDownOwnerAnnotation); v != "true" {
+		t.Errorf("Expected the owner annotation on the tainted node, got %q", v)
+	}
+	if mockKube.annotationPatches != 1 {
+		t.Errorf("Expected both annotations in a single patch, got %d patches", mockKube.annotationPatches)
+	}
+	if _, ok := mockKube.getAnnotation("pinned", ScaleDownOwnerAnnotation); ok {
+		t.Error("Expected existing scale-down annotation to be left alone")
+	}
+
+	mockPSI.results["node-1"] = &psi.NodePSI{}
+	mockPSI.results["pinned"] = &psi.NodePSI{}
+	time.Sleep(5 * time.Millisecond)
+	ctrl.pollAllNodes(context.Background())
+
+	if _, ok := mockKube.getAnnotation("node-1", ScaleDownDisabledAnnotation); ok {
+		t.Error("Expected scale-down annotation to be removed with the taint")
+	}
+	if _, ok := mockKube.getAnnotation("node-1", ScaleDownOwnerAnnotation); ok {
+		t.Error("Expected the owner annotation to be removed with the taint")
+	}
+	if mockKube.annotationPatches != 2 {
+		t.Errorf("Expected both annotations removed in a single patch, got %d patches", mockKube.annotationPatches)
+	}
+	if mockKube.getRemoveCalls() != 2 {
+		t.Errorf("Expected both taints to be removed, got %d removals", mockKube.getRemoveCalls())
+	}
+}
+
+func TestController_ReportTaintedCapacity(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Autoscaler.PoolLabel = "pool"
+
+	ctrl := NewController(cfg, newMockKubeClient(nil), psi.NewFetcher(nil), logger)
+	ctrl.nodes["a-1"] = &nodeState{tainted: true, applied: ctrl.actuators}
+	ctrl.nodes["a-2"] = &nodeState{}
+
+	nodes := []kubernetes.NodeInfo{
+		{Name: "a-1", Labels: map[string]string{"pool": "a"}, Allocatable: map[string]float64{"cpu": 4, "memory": 8}},
+		{Name: "a-2", Labels: map[string]string{"pool": "a"}, Allocatable: map[string]float64{"cpu": 4, "memory": 8}},
+		{Name: "b-1", Labels: map[string]string{"pool": "b"}, Allocatable: map[string]float64{"cpu": 2, "memory": 4}},
+	}
+	ctrl.reportTaintedCapacity(nodes)
+
+	if got := testutil.ToFloat64(metrics.PoolTaintedRatio.WithLabelValues("a")); got != 0.5 {
+		t.Errorf("pool a tainted ratio = %v, want 0.5", got)
+	}
+	if got := testutil.ToFloat64(metrics.PoolTaintedCapacity.WithLabelValues("a", "cpu")); got != 4 {
+		t.Errorf("pool a tainted cpu = %v, want 4", got)
+	}
+	if got := testutil.ToFloat64(metrics.PoolTaintedRatio.WithLabelValues("b")); got != 0 {
+		t.Errorf("pool b tainted ratio = %v, want 0", got)
+	}
+}
+
+func TestController_ConditionActuator(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = time.Millisecond
+	cfg.Actuators = []string{config.ActuatorCondition}
+	cfg.NodeCondition.Type = "PSIPressure"
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": {}}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+	if got := mockKube.getNodeCondition("node-1", "PSIPressure"); got != "false/"+actuator.ConditionReasonNormal {
+		t.Fatalf("condition = %q, want it initialised to False", got)
+	}
+
+	mockPSI.results["node-1"] = &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}}
+	ctrl.pollAllNodes(context.Background())
+	if got := mockKube.getNodeCondition("node-1", "PSIPressure"); got != "true/"+actuator.ConditionReasonHigh {
+		t.Errorf("condition = %q, want True under pressure", got)
+	}
+	if mockKube.getApplyCalls() != 0 {
+		t.Error("Expected no taint when only the condition actuator is configured")
+	}
+	raw, ok := mockKube.getAnnotation("node-1", actuator.ReasonAnnotation)
+	var reason actuator.PressureAnnotation
+	if !ok || json.Unmarshal([]byte(raw), &reason) != nil || reason.Resource != "cpu" || reason.Reason == "" {
+		t.Errorf("reason annotation = %q, want the cpu breach", raw)
+	}
+
+	mockPSI.results["node-1"] = &psi.NodePSI{}
+	time.Sleep(5 * time.Millisecond)
+	ctrl.pollAllNodes(context.Background())
+	if got := mockKube.getNodeCondition("node-1", "PSIPressure"); got != "false/"+actuator.ConditionReasonNormal {
+		t.Errorf("condition = %q, want False after recovery", got)
+	}
+	if _, ok := mockKube.getAnnotation("node-1", actuator.ReasonAnnotation); ok {
+		t.Error("Expected the reason annotation to be removed after recovery")
+	}
+	want := []string{"node-1/PressureActionsApplied", "node-1/PressureActionsRemoved"}
+	if events := mockKube.getEvents(); !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestController_ConditionActuator_AlongsideTaint(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Actuators = []string{config.ActuatorTaint, config.ActuatorCondition}
+	cfg.NodeCondition.Type = "PSIPressure"
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"node-1": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	if !mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected taint to be applied")
+	}
+	if got := mockKube.getNodeCondition("node-1", "PSIPressure"); got != "true/"+actuator.ConditionReasonHigh {
+		t.Errorf("condition = %q, want True", got)
+	}
+}
+
+func TestController_LabelActuator(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = time.Millisecond
+	cfg.Actuators = []string{config.ActuatorLabel}
+	cfg.NodeLabel.Key = "kube-dethrottler.io/pressure"
+	cfg.Thresholds.Memory.Some.Avg10 = 20
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": {}}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	steps := []struct {
+		name   string
+		sample *psi.NodePSI
+		want   string
+	}{
+		{"initialised", &psi.NodePSI{}, actuator.LabelNone},
+		{"cpu pressure", &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}}, "cpu"},
+		{"memory dominates", &psi.NodePSI{
+			CPU:    psi.Pressure{Some: psi.Averages{Avg10: 30}},
+			Memory: psi.Pressure{Some: psi.Averages{Avg10: 60}},
+		}, "memory"},
+		{"recovered", &psi.NodePSI{}, actuator.LabelNone},
+	}
+	for _, step := range steps {
+		mockPSI.results["node-1"] = step.sample
+		time.Sleep(5 * time.Millisecond)
+		ctrl.pollAllNodes(context.Background())
+		if got := mockKube.getLabel("node-1", cfg.NodeLabel.Key); got != step.want {
+			t.Errorf("%s: label = %q, want %q", step.name, got, step.want)
+		}
+	}
+	if mockKube.getApplyCalls() != 0 {
+		t.Error("Expected no taint when only the label actuator is configured")
+	}
+}
+
+func TestController_LabelActuator_ObservesExistingLabel(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Actuators = []string{config.ActuatorLabel}
+	cfg.NodeLabel.Key = "kube-dethrottler.io/pressure"
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockKube.labels = map[string]map[string]string{"node-1": {cfg.NodeLabel.Key: "io"}}
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": {}}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	state := ctrl.nodes["node-1"]
+	if state == nil || !state.tainted {
+		t.Fatal("Expected an existing pressure label to be treated as applied")
+	}
+	if got := mockKube.getLabel("node-1", cfg.NodeLabel.Key); got != "io" {
+		t.Errorf("label = %q, want it kept until the cooldown passes", got)
+	}
+}
+
+func TestDecision_DominantResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		breaches []breach
+		want     string
+	}{
+		{"highest ratio wins", []breach{
+			{resource: "cpu", value: 30, threshold: 25},
+			{resource: "io", value: 45, threshold: 30},
+		}, "io"},
+		{"trend signal", []breach{{signal: "memory.some.avg10.rise", value: 20, threshold: 10}}, "memory"},
+		{"trend outweighs threshold", []breach{
+			{resource: "cpu", value: 30, threshold: 25},
+			{signal: "memory.some.avg10.rise", value: 30, threshold: 10},
+		}, "memory"},
+		{"threshold outweighs trend", []breach{
+			{resource: "cpu", value: 75, threshold: 25},
+			{signal: "memory.some.avg10.rise", value: 11, threshold: 10},
+		}, "cpu"},
+		{"outlier", []breach{
+			{resource: "io", value: 33, threshold: 30},
+			{signal: "cpu.some.avg10.outlier", value: 60, threshold: 20},
+		}, "cpu"},
+		{"rule only", []breach{{rule: "custom", value: 1}}, actuator.ResourceOther},
+		{"no breaches", nil, actuator.ResourceOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := &Controller{now: time.Now}
+			sample := &psi.NodePSI{IO: psi.Pressure{Some: psi.Averages{Avg10: 12}}, Memory: psi.Pressure{Some: psi.Averages{Avg10: 7}}}
+			d := ctrl.decision(&nodeState{breaches: tt.breaches, sample: sample}, 1)
+			if d.Resource != tt.want {
+				t.Errorf("decision().Resource = %q, want %q", d.Resource, tt.want)
+			}
+			if d.Busiest != psi.ResourceIO {
+				t.Errorf("decision().Busiest = %q, want io", d.Busiest)
+			}
+			if tt.want != actuator.ResourceOther && d.Breaches[0].Resource != tt.want {
+				t.Errorf("decision().Breaches[0] = %+v, want the dominant %s breach first", d.Breaches[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestBusiestResource(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample *psi.NodePSI
+		want   string
+	}{
+		{"no sample", nil, psi.ResourceCPU},
+		{"idle", &psi.NodePSI{}, psi.ResourceCPU},
+		{"highest avg10", &psi.NodePSI{Memory: psi.Pressure{Some: psi.Averages{Avg10: 30}}}, psi.ResourceMemory},
+		{"missing ignored", &psi.NodePSI{
+			Memory:  psi.Pressure{Some: psi.Averages{Avg10: 30}},
+			IO:      psi.Pressure{Some: psi.Averages{Avg10: 90}},
+			Missing: []string{psi.ResourceIO},
+		}, psi.ResourceMemory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := busiestResource(tt.sample); got != tt.want {
+				t.Errorf("busiestResource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestController_Profiles(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.NodeLabel.Key = "kube-dethrottler.io/pressure"
+	cfg.Profiles = []config.Profile{
+		{Name: "affinity", NodeSelector: "pool=batch", Actuators: []string{config.ActuatorLabel}},
+	}
+
+	mockKube := newMockKubeClient([]string{"batch-1", "web-1"})
+	mockKube.labels = map[string]map[string]string{
+		"batch-1": {"pool": "batch"},
+		"web-1":   {"pool": "web"},
+	}
+	high := &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}}
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"batch-1": high, "web-1": high}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+
+	if mockKube.hasTaintForNode("batch-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected the affinity profile not to taint batch-1")
+	}
+	if got := mockKube.getLabel("batch-1", cfg.NodeLabel.Key); got != "cpu" {
+		t.Errorf("batch-1 label = %q, want cpu", got)
+	}
+	if !mockKube.hasTaintForNode("web-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected web-1 to be tainted by the default actuators")
+	}
+	if got := mockKube.getLabel("web-1", cfg.NodeLabel.Key); got != "" {
+		t.Errorf("web-1 label = %q, want none set", got)
+	}
+}
+
+func TestController_Profiles_ReleaseAppliedActions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = 0
+	cfg.NodeLabel.Key = "kube-dethrottler.io/pressure"
+	cfg.Profiles = []config.Profile{
+		{Name: "affinity", NodeSelector: "pool=batch", Actuators: []string{config.ActuatorLabel}},
+	}
+
+	mockKube := newMockKubeClient([]string{"web-1"})
+	mockKube.labels = map[string]map[string]string{"web-1": {"pool": "web"}}
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"web-1": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	ctrl.pollAllNodes(context.Background())
+	if !mockKube.hasTaintForNode("web-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Fatal("Expected web-1 to be tainted by the default actuators")
+	}
+
+	// The node moves to the affinity profile while tainted: the taint it
+	// carries is released, and the label it never got is left alone.
+	mockKube.setLabel("web-1", "pool", "batch")
+	mockPSI.results["web-1"] = &psi.NodePSI{}
+	ctrl.pollAllNodes(context.Background())
+
+	if mockKube.hasTaintForNode("web-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected the taint to be released after the profile changed")
+	}
+	if got := mockKube.getLabel("web-1", cfg.NodeLabel.Key); got != "" {
+		t.Errorf("web-1 label = %q, want none set", got)
+	}
+}
+
+func TestController_ObservedActions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Actuators = []string{config.ActuatorTaint, config.ActuatorCondition}
+	cfg.NodeCondition.Type = "PSIPressure"
+	taintKey := "node-1/" + cfg.TaintKey + "-" + cfg.TaintEffect
+	high := &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}}
+
+	tests := []struct {
+		name          string
+		taint         bool
+		sample        *psi.NodePSI
+		wantTainted   bool
+		wantTaint     bool
+		wantCondition string
+	}{
+		// The taint was removed by hand: only the taint is applied again.
+		{name: "condition left under pressure", sample: high, wantTainted: true, wantTaint: true},
+		{name: "condition left after pressure", sample: &psi.NodePSI{}, wantCondition: "false/" + actuator.ConditionReasonNormal},
+		{name: "taint left under pressure", taint: true, sample: high, wantTainted: true, wantTaint: true,
+			wantCondition: "true/" + actuator.ConditionReasonHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockKube := newMockKubeClient([]string{"node-1"})
+			mockKube.conditions = map[string]map[string]bool{"node-1": {"PSIPressure": !tt.taint}}
+			if tt.taint {
+				mockKube.taints[taintKey] = corev1.Taint{}
+			}
+			mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": tt.sample}}
+			ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+			ctrl.pollAllNodes(context.Background())
+
+			state := ctrl.nodes["node-1"]
+			if state.tainted != tt.wantTainted || (len(state.applied) == 2) != tt.wantTainted {
+				t.Errorf("tainted = %v, applied = %s, want tainted %v", state.tainted, describeActions(state.applied), tt.wantTainted)
+			}
+			if got := mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect); got != tt.wantTaint {
+				t.Errorf("taint = %v, want %v", got, tt.wantTaint)
+			}
+			wantApplyCalls := 0
+			if tt.wantTaint && !tt.taint {
+				wantApplyCalls = 1
+			}
+			if mockKube.applyCalls != wantApplyCalls {
+				t.Errorf("ApplyTaint called %d times, want %d", mockKube.applyCalls, wantApplyCalls)
+			}
+			if got := mockKube.getNodeCondition("node-1", "PSIPressure"); got != tt.wantCondition {
+				t.Errorf("condition = %q, want %q", got, tt.wantCondition)
+			}
+		})
+	}
+}
+
+func TestController_PartialActions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = 0
+	cfg.Actuators = []string{config.ActuatorTaint, config.ActuatorCondition}
+	cfg.NodeCondition.Type = "PSIPressure"
+	high := &psi.NodePSI{CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}}
+
+	t.Run("apply", func(t *testing.T) {
+		mockKube := newMockKubeClient([]string{"node-1"})
+		mockKube.applyTaintErr = errors.New("conflict")
+		mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": high}}
+		ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+		ctx := context.Background()
+
+		// The taint fails but the condition is still published.
+		ctrl.pollAllNodes(ctx)
+		state := ctrl.nodes["node-1"]
+		if got := mockKube.getNodeCondition("node-1", "PSIPressure"); got != "true/"+actuator.ConditionReasonHigh {
+			t.Errorf("condition = %q, want True despite the taint failure", got)
+		}
+		if state.tainted || len(state.applied) != 1 || state.applied[0].Name() != actuator.NameCondition {
+			t.Errorf("tainted = %v, applied = %s, want only the condition applied", state.tainted, describeActions(state.applied))
+		}
+		if state.action.Result != ResultFailed || !strings.Contains(state.action.Error, "taint") {
+			t.Errorf("action = %+v, want the taint failure", state.action)
+		}
+
+		// The next poll retries the taint alone.
+		mockKube.mu.Lock()
+		mockKube.applyTaintErr = nil
+		mockKube.mu.Unlock()
+		ctrl.pollAllNodes(ctx)
+		if !state.tainted || len(state.applied) != 2 {
+			t.Errorf("tainted = %v, applied = %s, want both actions applied", state.tainted, describeActions(state.applied))
+		}
+		if !mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect) {
+			t.Error("Expected the taint to be applied on retry")
+		}
+	})
+
+	t.Run("pressure subsides before the retry", func(t *testing.T) {
+		mockKube := newMockKubeClient([]string{"node-1"})
+		mockKube.applyTaintErr = errors.New("conflict")
+		mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": high}}
+		ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+		ctx := context.Background()
+
+		ctrl.pollAllNodes(ctx)
+		mockPSI.results["node-1"] = &psi.NodePSI{}
+		ctrl.pollAllNodes(ctx)
+
+		if got := mockKube.getNodeCondition("node-1", "PSIPressure"); got != "false/"+actuator.ConditionReasonNormal {
+			t.Errorf("condition = %q, want the partially applied condition reverted", got)
+		}
+		if state := ctrl.nodes["node-1"]; len(state.applied) != 0 {
+			t.Errorf("applied = %s, want none", describeActions(state.applied))
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		mockKube := newMockKubeClient([]string{"node-1"})
+		mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{"node-1": high}}
+		ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+		ctx := context.Background()
+
+		ctrl.pollAllNodes(ctx)
+		mockKube.mu.Lock()
+		mockKube.removeTaintErr = errors.New("conflict")
+		mockKube.mu.Unlock()
+		mockPSI.results["node-1"] = &psi.NodePSI{}
+		ctrl.pollAllNodes(ctx)
+
+		// The condition is reverted although the taint could not be removed.
+		state := ctrl.nodes["node-1"]
+		if got := mockKube.getNodeCondition("node-1", "PSIPressure"); got != "false/"+actuator.ConditionReasonNormal {
+			t.Errorf("condition = %q, want False despite the taint failure", got)
+		}
+		if !state.tainted || len(state.applied) != 1 || state.applied[0].Name() != actuator.NameTaint {
+			t.Errorf("tainted = %v, applied = %s, want only the taint left", state.tainted, describeActions(state.applied))
+		}
+
+		mockKube.mu.Lock()
+		mockKube.removeTaintErr = nil
+		mockKube.mu.Unlock()
+		ctrl.pollAllNodes(ctx)
+		if state.tainted || mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect) {
+			t.Error("Expected the taint to be released on retry")
+		}
+	})
+}
+
+func TestController_CloudEvents(t *testing.T) {
+	var mu sync.Mutex
+	var types []string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		types = append(types, r.Header.Get("ce-type")+"/"+r.Header.Get("ce-subject"))
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.CooldownPeriod = time.Millisecond
+	cfg.CloudEvents = config.CloudEvents{Enabled: true, URL: server.URL, Source: "test", Timeout: time.Second}
+
+	mockKube := newMockKubeClient([]string{"node-1"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"node-1": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	ctx := context.Background()
+
+	ctrl.pollAllNodes(ctx)
+	ctrl.pollAllNodes(ctx)
+	mockPSI.results["node-1"] = &psi.NodePSI{}
+	time.Sleep(5 * time.Millisecond)
+	ctrl.pollAllNodes(ctx)
+	mockPSI.err = errors.New("kubelet unreachable")
+	ctrl.pollAllNodes(ctx)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	ctrl.cloudEvents.Run(runCtx)
+
+	want := []string{
+		notify.TypePressureDetected + "/node-1",
+		notify.TypeTainted + "/node-1",
+		notify.TypeUntainted + "/node-1",
+		notify.TypePollFailed + "/node-1",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(types, want) {
+		t.Errorf("events = %v, want %v", types, want)
+	}
+}
+
+func TestController_DebugEndpoints(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Thresholds.CPU.Some.Avg60 = 40
+
+	mockKube := newMockKubeClient([]string{"node-1", "node-2"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"node-1": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50, Avg60: 30}}},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	ctrl.pollAllNodes(context.Background())
+
+	server := httptest.NewServer(ctrl.DebugHandler())
+	defer server.Close()
+
+	get := func(path string, want int, v any) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("GET %s status = %d, want %d", path, resp.StatusCode, want)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+	}
+
+	var all []Evaluation
+	get("/debug/nodes", http.StatusOK, &all)
+	if len(all) != 2 || all[0].Node != "node-1" || all[1].Node != "node-2" {
+		t.Fatalf("GET /debug/nodes = %+v, want node-1 and node-2", all)
+	}
+	if all[1].Error == "" {
+		t.Error("Expected the fetch error of node-2 to be recorded")
+	}
+
+	var e Evaluation
+	get("/debug/nodes/node-1", http.StatusOK, &e)
+	wantChecks := []Check{
+		{Name: "cpu.some.avg10", Value: 50, Threshold: 25, Fired: true},
+		{Name: "cpu.some.avg60", Value: 30, Threshold: 40},
+	}
+	if !slices.Equal(e.Checks, wantChecks) {
+		t.Errorf("Checks = %+v, want %+v", e.Checks, wantChecks)
+	}
+	if !slices.Equal(e.Fired, []string{"cpu.some.avg10"}) {
+		t.Errorf("Fired = %v, want [cpu.some.avg10]", e.Fired)
+	}
+	if !e.Tainted || e.TaintedSince == nil || e.CooldownRemainingSeconds <= 0 {
+		t.Errorf("Evaluation = %+v, want tainted with cooldown remaining", e)
+	}
+	if e.Action.Result != ResultApplied || e.Action.Actions != "taint kube-dethrottler/high-load:NoSchedule" {
+		t.Errorf("Action = %+v, want taint applied", e.Action)
+	}
+	if e.PSI == nil || e.PSI.CPU.Some.Avg10 != 50 {
+		t.Errorf("PSI = %+v, want the evaluated sample", e.PSI)
+	}
+
+	var notFound map[string]string
+	get("/debug/nodes/node-3", http.StatusNotFound, &notFound)
+	if notFound["error"] == "" {
+		t.Error("Expected an error message for an unknown node")
+	}
+
+	mockKube.nodeNames = []string{"node-1"}
+	ctrl.pollAllNodes(context.Background())
+	if _, ok := ctrl.Evaluation("node-2"); ok {
+		t.Error("Expected the evaluation of a removed node to be dropped")
+	}
+}
+
+func TestController_CheckNodes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Actuators = []string{config.ActuatorTaint, config.ActuatorCondition}
+	cfg.NodeCondition.Type = "PSIPressure"
+	cfg.MissingData.Policy = config.MissingDataBreach
+	cfg.NodeLifecycle.NotReady = config.LifecycleSkip
+
+	mockKube := newMockKubeClient([]string{"hot", "cold", "missing", "not-ready"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"hot":       {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}},
+		"cold":      {CPU: psi.Pressure{Some: psi.Averages{Avg10: 5}}},
+		"missing":   {Missing: []string{psi.ResourceCPU}},
+		"not-ready": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+
+	nodes := []kubernetes.NodeInfo{{Name: "hot"}, {Name: "cold"}, {Name: "missing"}, {Name: "not-ready", NotReady: true}, {Name: "gone"}}
+	evaluations := ctrl.CheckNodes(context.Background(), nodes)
+
+	want := map[string]struct {
+		fired   []string
+		skipped string
+		err     bool
+	}{
+		"hot":       {fired: []string{"cpu.some.avg10"}},
+		"cold":      {fired: []string{}},
+		"missing":   {fired: []string{"missingData"}},
+		"not-ready": {fired: []string{}, skipped: "notReady"},
+		"gone":      {fired: []string{}, err: true},
+	}
+	for _, e := range evaluations {
+		w := want[e.Node]
+		if !slices.Equal(e.Fired, w.fired) || e.Skipped != w.skipped || (e.Error != "") != w.err {
+			t.Errorf("%s: fired = %v, skipped = %q, error = %q; want %v, %q, error %v", e.Node, e.Fired, e.Skipped, e.Error, w.fired, w.skipped, w.err)
+		}
+	}
+
+	if mockKube.getApplyCalls() != 0 || len(mockKube.getEvents()) != 0 || mockKube.getNodeCondition("hot", "PSIPressure") != "" {
+		t.Error("Expected CheckNodes not to modify the cluster")
+	}
+	if len(ctrl.nodes) != 0 || len(ctrl.Evaluations()) != 0 {
+		t.Error("Expected CheckNodes not to record controller state")
+	}
+}
+
+func TestController_Pause(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := testConfig()
+	cfg.Pause = config.Pause{Enabled: true, ConfigMap: "kube-dethrottler-pause", Namespace: "kube-system"}
+
+	mockKube := newMockKubeClient([]string{"node-1", "node-2"})
+	mockPSI := &mockPSIFetcher{results: map[string]*psi.NodePSI{
+		"node-1": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 50}}},
+		"node-2": {},
+	}}
+	ctrl := newControllerWithMockPSI(cfg, mockKube, mockPSI, logger)
+	pause := func(value string) {
+		mockKube.setConfigMapValue("kube-system", "kube-dethrottler-pause", config.PauseKey, value)
+	}
+
+	// Paused: node-1 is evaluated but not tainted.
+	pause("true")
+	ctrl.pollAllNodes(context.Background())
+	if mockKube.getApplyCalls() != 0 {
+		t.Fatal("Expected no taint while paused")
+	}
+	if e, ok := ctrl.Evaluation("node-1"); !ok || e.Action.Result != ResultPaused || len(e.Fired) != 1 {
+		t.Errorf("Evaluation while paused = %+v, want the breach with result %q", e, ResultPaused)
+	}
+
+	// Resumed: node-1 is tainted.
+	pause("false")
+	ctrl.pollAllNodes(context.Background())
+	if !mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Fatal("Expected node-1 to be tainted after resuming")
+	}
+
+	// The taint is removed by hand while paused and reapplied on resume.
+	pause("true")
+	ctrl.pollAllNodes(context.Background())
+	if err := mockKube.RemoveTaint(context.Background(), "node-1", cfg.TaintKey, cfg.TaintEffect); err != nil {
+		t.Fatal(err)
+	}
+	ctrl.pollAllNodes(context.Background())
+	ctrl.cleanupTaints()
+	if mockKube.getRemoveCalls() != 1 {
+		t.Errorf("RemoveTaint calls = %d, want only the manual removal while paused", mockKube.getRemoveCalls())
+	}
+	state := ctrl.nodes["node-1"]
+	state.cycles = 3
+	pause("false")
+	ctrl.pollAllNodes(context.Background())
+	if !mockKube.hasTaintForNode("node-1", cfg.TaintKey, cfg.TaintEffect) {
+		t.Error("Expected node-1 to be tainted again after resuming")
+	}
+	// Only whether the actions are in place is observed again.
+	if ctrl.nodes["node-1"] != state || state.cycles < 3 {
+		t.Errorf("Expected the state of node-1 to be kept on resume, got %+v", ctrl.nodes["node-1"])
+	}
+}
@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+// Results of the pressure actions in an Evaluation.
+const (
+	ResultNone      = "none"
+	ResultApplied   = "applied"
+	ResultRefreshed = "refreshed"
+	ResultReleased  = "released"
+	ResultFailed    = "failed"
+	// ResultPaused means the node was evaluated while the pause switch
+	// suspended all actions.
+	ResultPaused = "paused"
+)
+
+// Evaluation is the outcome of the latest poll of a node, served under
+// /debug/nodes to answer why a node is, or is not, under pressure.
+type Evaluation struct {
+	Time time.Time `json:"time"`
+	Node string    `json:"node"`
+	// Schedule is the threshold schedule in effect.
+	Schedule string `json:"schedule"`
+	// PSI is the evaluated sample, after smoothing, without pod data.
+	PSI *psi.NodePSI `json:"psi,omitempty"`
+	// Checks lists every configured PSI threshold and every other breach.
+	Checks []Check `json:"checks"`
+	// Fired names the checks that breached.
+	Fired        []string   `json:"fired"`
+	Tainted      bool       `json:"tainted"`
+	TaintedSince *time.Time `json:"taintedSince,omitempty"`
+	// CooldownRemainingSeconds is the time left before a tainted node may
+	// be released once its pressure subsides.
+	CooldownRemainingSeconds float64 `json:"cooldownRemainingSeconds"`
+	Action                   Action  `json:"action"`
+	// Skipped is set when the node was not evaluated, e.g. "notReady" or
+	// "unknown", and Error when the poll failed.
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Check is a single threshold comparison.
+type Check struct {
+	Name      string  `json:"name"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	// Below is set for checks that breach when the value drops under the
+	// threshold.
+	Below bool `json:"below,omitempty"`
+	Fired bool `json:"fired"`
+}
+
+// Action is the result of the pressure actions during an evaluation.
+type Action struct {
+	Actions string `json:"actions"`
+	Result  string `json:"result"`
+	Error   string `json:"error,omitempty"`
+}
+
+// recordEvaluation stores the evaluation of a sampled node once the
+// decision was made.
+func (c *Controller) recordEvaluation(s sampledNode) {
+	c.storeEvaluation(c.evaluation(s))
+}
+
+// evaluation describes the latest evaluation of a sampled node.
+func (c *Controller) evaluation(s sampledNode) *Evaluation {
+	state := s.state
+	e := &Evaluation{
+		Time:     c.now(),
+		Node:     s.name,
+		Schedule: scheduleName(c.schedule),
+		Checks:   c.checks(state),
+		Fired:    []string{},
+		Tainted:  state.tainted,
+		Action:   state.action,
+	}
+	if state.sample != nil {
+		sample := *state.sample
+		sample.Pods = nil
+		e.PSI = &sample
+	}
+	if s.dataBreach {
+		e.Checks = append(e.Checks, Check{Name: "missingData", Fired: true})
+	}
+	for _, check := range e.Checks {
+		if check.Fired {
+			e.Fired = append(e.Fired, check.Name)
+		}
+	}
+	if state.tainted && !state.taintedSince.IsZero() {
+		since := state.taintedSince
+		e.TaintedSince = &since
+		remaining := c.effectiveCooldown(state) - c.now().Sub(state.lastTaintTime)
+		e.CooldownRemainingSeconds = max(remaining, 0).Seconds()
+	}
+	return e
+}
+
+// recordSkipped stores the evaluation of a node that was skipped or whose
+// poll failed.
+func (c *Controller) recordSkipped(nodeName, skipped string, err error) {
+	e := &Evaluation{
+		Time:     c.now(),
+		Node:     nodeName,
+		Schedule: scheduleName(c.schedule),
+		Checks:   []Check{},
+		Fired:    []string{},
+		Action:   Action{Result: ResultNone},
+		Skipped:  skipped,
+	}
+	if state, ok := c.nodes[nodeName]; ok {
+		e.Tainted = state.tainted
+		e.Action.Actions = describeActions(c.actuatorsFor(state.node))
+		if len(state.applied) > 0 {
+			e.Action.Actions = describeActions(state.applied)
+		}
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	c.storeEvaluation(e)
+}
+
+func (c *Controller) storeEvaluation(e *Evaluation) {
+	c.evaluationsMu.Lock()
+	defer c.evaluationsMu.Unlock()
+	c.evaluations[e.Node] = e
+}
+
+// pruneEvaluations drops the evaluations of nodes that no longer exist.
+func (c *Controller) pruneEvaluations(nodes []kubernetes.NodeInfo) {
+	c.evaluationsMu.Lock()
+	defer c.evaluationsMu.Unlock()
+	for name := range c.evaluations {
+		if !slices.ContainsFunc(nodes, func(n kubernetes.NodeInfo) bool { return n.Name == name }) {
+			delete(c.evaluations, name)
+		}
+	}
+}
+
+// Evaluations returns the latest evaluation of every node, sorted by name.
+func (c *Controller) Evaluations() []Evaluation {
+	c.evaluationsMu.RLock()
+	defer c.evaluationsMu.RUnlock()
+	evaluations := make([]Evaluation, 0, len(c.evaluations))
+	for _, e := range c.evaluations {
+		evaluations = append(evaluations, *e)
+	}
+	slices.SortFunc(evaluations, func(a, b Evaluation) int { return strings.Compare(a.Node, b.Node) })
+	return evaluations
+}
+
+// Evaluation returns the latest evaluation of a node.
+func (c *Controller) Evaluation(nodeName string) (Evaluation, bool) {
+	c.evaluationsMu.RLock()
+	defer c.evaluationsMu.RUnlock()
+	e, ok := c.evaluations[nodeName]
+	if !ok {
+		return Evaluation{}, false
+	}
+	return *e, true
+}
+
+// checks lists the PSI threshold comparisons of the node's sample and its
+// other breaches.
+func (c *Controller) checks(state *nodeState) []Check {
+	fired := make(map[string]bool, len(state.breaches))
+	for _, b := range state.breaches {
+		fired[b.name()] = true
+	}
+
+	checks := []Check{}
+	if state.sample != nil {
+		t := c.thresholds()
+		for _, r := range []struct {
+			resource  string
+			actual    psi.Pressure
+			threshold config.PSIPressure
+		}{
+			{psi.ResourceCPU, state.sample.CPU, t.CPU},
+			{psi.ResourceMemory, state.sample.Memory, t.Memory},
+			{psi.ResourceIO, state.sample.IO, t.IO},
+		} {
+			checks = appendAverageChecks(checks, fired, r.resource+".some", r.actual.Some, r.threshold.Some)
+			checks = appendAverageChecks(checks, fired, r.resource+".full", r.actual.Full, r.threshold.Full)
+		}
+	}
+	for _, b := range state.breaches {
+		if b.resource == "" {
+			checks = append(checks, Check{Name: b.name(), Value: b.value, Threshold: b.threshold, Below: b.below, Fired: true})
+		}
+	}
+	return checks
+}
+
+func appendAverageChecks(checks []Check, fired map[string]bool, prefix string, actual psi.Averages, threshold config.PSIAverages) []Check {
+	for _, w := range []struct {
+		name      string
+		value     float64
+		threshold float64
+	}{
+		{"avg10", actual.Avg10, threshold.Avg10},
+		{"avg60", actual.Avg60, threshold.Avg60},
+		{"avg300", actual.Avg300, threshold.Avg300},
+	} {
+		if w.threshold <= 0 {
+			continue
+		}
+		name := prefix + "." + w.name
+		checks = append(checks, Check{Name: name, Value: w.value, Threshold: w.threshold, Fired: fired[name]})
+	}
+	return checks
+}
+
+// DebugHandler serves the latest evaluations as JSON: GET /debug/nodes
+// lists every node and GET /debug/nodes/{name} returns a single node.
+func (c *Controller) DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/nodes", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, c.Evaluations())
+	})
+	mux.HandleFunc("GET /debug/nodes/{name}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		e, ok := c.Evaluation(name)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no evaluation for node " + name})
+			return
+		}
+		writeJSON(w, http.StatusOK, e)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+// maybeEvict evicts the highest-PSI allowlisted pod from a node that has
+// been tainted for at least Eviction.TaintedFor while full pressure is
+// still above the critical level, subject to per-node and cluster-wide
+// rate limits.
+func (c *Controller) maybeEvict(ctx context.Context, nodeName string, state *nodeState) {
+	cfg := c.config.Eviction
+	if !cfg.Enabled || state.sample == nil {
+		return
+	}
+	now := c.now()
+	if now.Sub(state.taintedSince) < cfg.TaintedFor {
+		return
+	}
+	if !c.fullPressureCritical(state) {
+		return
+	}
+	if now.Sub(state.lastEviction) < cfg.NodeInterval || now.Sub(c.lastEviction) < cfg.ClusterInterval {
+		return
+	}
+	// Retrying a blocked eviction on every poll only repeats the refusal.
+	if now.Sub(state.evictionBlockedAt) < cfg.NodeInterval {
+		return
+	}
+
+	pod, ok := c.evictionCandidate(ctx, nodeName, state)
+	if !ok {
+		return
+	}
+
+	c.logger.Warn("Full pressure critical after tainting, evicting pod", logging.Node(nodeName), logging.Pod(pod.namespace, pod.name),
+		logging.Resource(pod.resource), logging.Value(pod.value), slog.Float64(logging.KeyThreshold, cfg.CriticalFullAvg10),
+		slog.Duration("tainted", now.Sub(state.taintedSince).Round(time.Second)))
+	err := c.kubeClient.EvictPod(ctx, pod.namespace, pod.name)
+	switch {
+	case errors.Is(err, kubernetes.ErrEvictionBlocked):
+		c.logger.Warn("Eviction blocked, retrying after nodeInterval", logging.Node(nodeName), logging.Pod(pod.namespace, pod.name), logging.Err(err))
+		state.evictionBlockedAt = now
+		metrics.Evictions.WithLabelValues(nodeName, "blocked").Inc()
+	case err != nil:
+		c.logger.Error("Error evicting pod", logging.Node(nodeName), logging.Pod(pod.namespace, pod.name), logging.Err(err))
+		metrics.Evictions.WithLabelValues(nodeName, "error").Inc()
+	default:
+		state.lastEviction = now
+		c.lastEviction = now
+		metrics.Evictions.WithLabelValues(nodeName, "success").Inc()
+		c.recordEvent(ctx, nodeName, corev1.EventTypeWarning, "NoisyPodEvicted",
+			fmt.Sprintf("Evicted pod %s/%s (%s=%.2f) to relieve sustained pressure", pod.namespace, pod.name, pod.resource, pod.value))
+	}
+}
+
+// fullPressureCritical reports whether full.avg10 of any resource is above
+// the critical eviction level.
+func (c *Controller) fullPressureCritical(state *nodeState) bool {
+	critical := c.config.Eviction.CriticalFullAvg10
+	s := state.sample
+	return s.CPU.Full.Avg10 > critical || s.Memory.Full.Avg10 > critical || s.IO.Full.Avg10 > critical
+}
+
+// evictionCandidate returns the highest ranked pod on the node that is in
+// an allowed namespace and matches the pod selector.
+func (c *Controller) evictionCandidate(ctx context.Context, nodeName string, state *nodeState) (podContribution, bool) {
+	cfg := c.config.Eviction
+	selector, err := labels.Parse(cfg.PodSelector)
+	if err != nil {
+		c.logger.Error("Invalid eviction pod selector", slog.String("selector", cfg.PodSelector), logging.Err(err))
+		return podContribution{}, false
+	}
+
+	for _, pod := range c.rankPods(state) {
+		if len(cfg.Namespaces) > 0 && !slices.Contains(cfg.Namespaces, pod.namespace) {
+			continue
+		}
+		if !selector.Empty() {
+			podLabels, err := c.kubeClient.GetPodLabels(ctx, pod.namespace, pod.name)
+			if err != nil {
+				c.logger.Error("Error getting pod labels", logging.Node(nodeName), logging.Pod(pod.namespace, pod.name), logging.Err(err))
+				continue
+			}
+			if !selector.Matches(labels.Set(podLabels)) {
+				continue
+			}
+		}
+		return pod, true
+	}
+	return podContribution{}, false
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+// lifecycleReasons explain taint removals caused by the untaint policy.
+var lifecycleReasons = map[string]string{
+	"deleting":      "because the node is being deleted",
+	"notReady":      "because the node is NotReady",
+	"unschedulable": "because the node is cordoned",
+}
+
+// lifecycleState returns the node's lifecycle state that has a configured
+// policy, and that policy. It returns "" when the node is in service.
+func (c *Controller) lifecycleState(node kubernetes.NodeInfo) (state, policy string) {
+	l := c.config.NodeLifecycle
+	switch {
+	case node.Deleting:
+		return "deleting", l.Deleting
+	case node.NotReady:
+		return "notReady", l.NotReady
+	case node.Unschedulable:
+		return "unschedulable", l.Unschedulable
+	}
+	return "", ""
+}
+
+// skipForLifecycle applies the lifecycle policy for the node and reports
+// whether it must be skipped for this poll.
+func (c *Controller) skipForLifecycle(ctx context.Context, node kubernetes.NodeInfo) bool {
+	lifecycle, policy := c.lifecycleState(node)
+	if policy == config.LifecycleEvaluate {
+		lifecycle = ""
+	}
+
+	previous, wasSkipped := c.skipped[node.Name]
+	if lifecycle == "" {
+		if wasSkipped {
+			c.logger.Info("Resuming evaluation", logging.Node(node.Name), slog.String("lifecycle", previous))
+			delete(c.skipped, node.Name)
+			metrics.NodeSkipped.DeletePartialMatch(map[string]string{"node": node.Name})
+		}
+		return false
+	}
+
+	if previous != lifecycle {
+		c.logger.Info("Applying nodeLifecycle policy", logging.Node(node.Name), slog.String("lifecycle", lifecycle), slog.String("policy", policyOrDefault(policy)))
+		metrics.NodeSkipped.DeletePartialMatch(map[string]string{"node": node.Name})
+		metrics.NodeSkipped.WithLabelValues(node.Name, lifecycle).Set(1)
+		c.skipped[node.Name] = lifecycle
+	}
+
+	if policy == config.LifecycleUntaint {
+		if state, ok := c.nodeState(ctx, node); ok && len(state.applied) > 0 {
+			state.node = node
+			c.logger.Info("Releasing node", logging.Node(node.Name), slog.String("lifecycle", lifecycle), logging.Action(describeActions(state.applied)))
+			c.release(ctx, node.Name, state, lifecycleReasons[lifecycle])
+		}
+	}
+	return true
+}
+
+func policyOrDefault(policy string) string {
+	if policy == "" {
+		return config.LifecycleSkip
+	}
+	return policy
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+// detectOutliers groups the sampled nodes into pools and appends a breach
+// to every node whose value of a configured metric is above its pool's
+// outlier bound.
+func (c *Controller) detectOutliers(pass []sampledNode) {
+	o := c.config.Outliers
+
+	pools := make(map[string][]sampledNode)
+	for _, s := range pass {
+		pool := "all"
+		if o.PoolLabel != "" {
+			pool = s.labels[o.PoolLabel]
+		}
+		pools[pool] = append(pools[pool], s)
+	}
+
+	for pool, nodes := range pools {
+		for _, metric := range o.Metrics {
+			resource := strings.SplitN(metric, ".", 2)[0]
+
+			var members []sampledNode
+			var values []float64
+			for _, s := range nodes {
+				if s.state.sample == nil || s.state.sample.IsMissing(resource) {
+					continue
+				}
+				v, _ := s.state.sample.Value(metric)
+				members = append(members, s)
+				values = append(values, v)
+			}
+			if len(values) < o.MinNodes {
+				metrics.OutlierBound.DeleteLabelValues(pool, metric)
+				continue
+			}
+
+			bound := outlierBound(o, values)
+			metrics.OutlierBound.WithLabelValues(pool, metric).Set(bound)
+
+			for i, s := range members {
+				if values[i] <= bound {
+					continue
+				}
+				b := breach{signal: metric + ".outlier", value: values[i], threshold: bound}
+				c.logBreach(s.name, b)
+				s.state.breaches = append(s.state.breaches, b)
+			}
+		}
+	}
+}
+
+// outlierBound computes the value above which a pool member is an outlier,
+// clamped to the configured floor and ceiling.
+func outlierBound(o config.Outliers, values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	var bound float64
+	switch o.Method {
+	case config.OutlierPercentile:
+		bound = o.Factor * percentile(sorted, o.Percentile)
+	default:
+		median := percentile(sorted, 50)
+		deviations := make([]float64, len(sorted))
+		for i, v := range sorted {
+			deviations[i] = math.Abs(v - median)
+		}
+		slices.Sort(deviations)
+		bound = median + o.Factor*percentile(deviations, 50)
+	}
+
+	return math.Min(math.Max(bound, o.Floor), o.Ceiling)
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating
+// linearly between the closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
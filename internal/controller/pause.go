@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+// updatePaused reads the pause switch and reports whether changes to nodes
+// are suspended. If the switch cannot be read, the previous state holds.
+func (c *Controller) updatePaused(ctx context.Context, nodes []kubernetes.NodeInfo) bool {
+	p := c.config.Pause
+	if !p.Enabled {
+		return false
+	}
+	value, err := c.kubeClient.GetConfigMapValue(ctx, p.Namespace, p.ConfigMap, config.PauseKey)
+	if err != nil {
+		c.logger.Error("Error reading the pause switch", logging.Err(err))
+		metrics.PollErrors.WithLabelValues("", "pause").Inc()
+		return c.paused
+	}
+
+	paused := value == "true"
+	if paused != c.paused {
+		switch {
+		case paused:
+			c.logger.Warn("Paused, suspending all changes to nodes", slog.String("configMap", p.Namespace+"/"+p.ConfigMap))
+		default:
+			c.logger.Info("Resumed, observing the actions in place on every node")
+			c.reobserve(ctx, nodes)
+		}
+		metrics.Paused.Set(boolToFloat(paused))
+	}
+	c.paused = paused
+	return paused
+}
+
+// reobserve updates whether the actions are in place on the known nodes.
+// They may have been reverted by hand while paused, e.g. with untaint-all;
+// the rest of the nodes' state, such as the cooldown backoff, smoothing and
+// history, is kept.
+func (c *Controller) reobserve(ctx context.Context, nodes []kubernetes.NodeInfo) {
+	for _, node := range nodes {
+		state, ok := c.nodes[node.Name]
+		if !ok {
+			continue
+		}
+		acts := state.applied
+		if !state.tainted {
+			acts = mergeActuators(state.applied, c.actuatorsFor(node))
+		}
+		applied, err := actuated(ctx, acts, node)
+		if err != nil {
+			c.logger.Error("Error checking actions", logging.Node(node.Name), logging.Action(describeActions(acts)), logging.Err(err))
+			continue
+		}
+		// Actions partly reverted by hand are completed or released like
+		// a partial apply at the next poll.
+		wasTainted := state.tainted
+		state.applied = applied
+		state.tainted = len(applied) == len(acts)
+		switch {
+		case state.tainted && !wasTainted:
+			state.lastTaintTime = c.now()
+			state.taintedSince = state.lastTaintTime
+			c.logger.Info("Actions applied while paused", logging.Node(node.Name), logging.Action(describeActions(acts)))
+		case len(applied) == 0 && wasTainted:
+			// The annotations that go with the actions were removed too.
+			state.topPods = ""
+			state.scaleDownDisabled = false
+			c.logger.Info("Actions released while paused", logging.Node(node.Name), logging.Action(describeActions(acts)))
+		case !state.tainted && wasTainted:
+			c.logger.Info("Actions partially released while paused", logging.Node(node.Name), logging.Action(describeActions(applied)))
+		}
+	}
+}
+
+// pollPaused evaluates the nodes without acting on the results.
+func (c *Controller) pollPaused(ctx context.Context, nodes []kubernetes.NodeInfo) {
+	for _, e := range c.CheckNodes(ctx, nodes) {
+		e.Action.Result = ResultPaused
+		c.storeEvaluation(&e)
+	}
+}
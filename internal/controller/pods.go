@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+// podContribution is a pod's pressure on the resource that ranks it highest.
+type podContribution struct {
+	namespace string
+	name      string
+	resource  string
+	value     float64
+}
+
+func (p podContribution) String() string {
+	return fmt.Sprintf("%s/%s(%s=%.2f)", p.namespace, p.name, p.resource, p.value)
+}
+
+// topPods returns the PodAnalysis.TopN highest ranked pods of the node.
+func (c *Controller) topPods(state *nodeState) []podContribution {
+	pods := c.rankPods(state)
+	if len(pods) > c.config.PodAnalysis.TopN {
+		pods = pods[:c.config.PodAnalysis.TopN]
+	}
+	return pods
+}
+
+// rankPods orders the pods in the node's latest sample by their pressure on
+// the windows that breached, falling back to some.avg10 of every monitored
+// resource when no individual threshold was exceeded.
+func (c *Controller) rankPods(state *nodeState) []podContribution {
+	if state.sample == nil || len(state.sample.Pods) == 0 {
+		return nil
+	}
+
+	var keys []breach
+	for _, b := range state.breaches {
+		if b.resource != "" {
+			keys = append(keys, b)
+		}
+	}
+	if len(keys) == 0 {
+		for _, r := range c.monitoredResources() {
+			keys = append(keys, breach{resource: r, kind: "some", window: "avg10"})
+		}
+	}
+
+	pods := make([]podContribution, 0, len(state.sample.Pods))
+	for i := range state.sample.Pods {
+		pod := &state.sample.Pods[i]
+		best := podContribution{namespace: pod.Namespace, name: pod.Name}
+		for _, k := range keys {
+			pressure := pod.ForResource(k.resource)
+			if pressure == nil {
+				continue
+			}
+			if v, ok := pressure.Value(k.kind, k.window); ok && v > best.value {
+				best.resource, best.value = k.resource, v
+			}
+		}
+		if best.value > 0 {
+			pods = append(pods, best)
+		}
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].value != pods[j].value {
+			return pods[i].value > pods[j].value
+		}
+		return pods[i].namespace+"/"+pods[i].name < pods[j].namespace+"/"+pods[j].name
+	})
+	return pods
+}
+
+// reportTopPods publishes the top pressure-contributing pods of a tainted
+// node to logs, metrics and a node annotation whenever the ranking changes,
+// and returns the formatted list.
+func (c *Controller) reportTopPods(ctx context.Context, nodeName string, state *nodeState) string {
+	if !c.config.PodAnalysis.Enabled {
+		return ""
+	}
+
+	pods := c.topPods(state)
+	parts := make([]string, 0, len(pods))
+	for _, p := range pods {
+		parts = append(parts, p.String())
+	}
+	summary := strings.Join(parts, ", ")
+	if summary == state.topPods {
+		return summary
+	}
+	state.topPods = summary
+
+	metrics.PodPressure.DeletePartialMatch(prometheus.Labels{"node": nodeName})
+	for _, p := range pods {
+		metrics.PodPressure.WithLabelValues(nodeName, p.namespace, p.name, p.resource).Set(p.value)
+	}
+
+	key := c.config.PodAnalysis.AnnotationKey
+	if summary == "" {
+		if err := c.kubeClient.RemoveNodeAnnotation(ctx, nodeName, key); err != nil {
+			c.logger.Error("Error removing annotation", logging.Node(nodeName), slog.String("annotation", key), logging.Err(err))
+		}
+		return summary
+	}
+
+	c.logger.Info("Top pressure-contributing pods", logging.Node(nodeName), slog.String("pods", summary))
+	if err := c.kubeClient.SetNodeAnnotation(ctx, nodeName, key, summary); err != nil {
+		c.logger.Error("Error setting annotation", logging.Node(nodeName), slog.String("annotation", key), logging.Err(err))
+	}
+	return summary
+}
+
+// clearTopPods removes the top pods report once a node is untainted.
+func (c *Controller) clearTopPods(ctx context.Context, nodeName string, state *nodeState) {
+	if !c.config.PodAnalysis.Enabled || state.topPods == "" {
+		return
+	}
+	state.topPods = ""
+	metrics.PodPressure.DeletePartialMatch(prometheus.Labels{"node": nodeName})
+
+	key := c.config.PodAnalysis.AnnotationKey
+	if err := c.kubeClient.RemoveNodeAnnotation(ctx, nodeName, key); err != nil {
+		c.logger.Error("Error removing annotation", logging.Node(nodeName), slog.String("annotation", key), logging.Err(err))
+	}
+}
+
+// describeBreaches summarizes why a node was considered under pressure.
+func describeBreaches(state *nodeState) string {
+	if len(state.breaches) == 0 {
+		if len(state.unavailable) > 0 {
+			return "PSI data unavailable for " + strings.Join(state.unavailable, ", ")
+		}
+		return "thresholds exceeded"
+	}
+	parts := make([]string, 0, len(state.breaches))
+	for _, b := range state.breaches {
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, ", ")
+}
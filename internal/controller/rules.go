@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+	"github.com/Fedosin/kube-dethrottler/internal/rules"
+)
+
+// evaluateRules evaluates the configured rule expressions against the sample
+// and appends a breach for each rule that matches.
+func (c *Controller) evaluateRules(breaches []breach, nodePSI *psi.NodePSI, nodeName string) []breach {
+	if len(c.rules) == 0 {
+		return breaches
+	}
+
+	vars := rules.Vars(nodePSI)
+	for _, rule := range c.rules {
+		matched, err := rule.Eval(vars)
+		if err != nil {
+			c.logger.Error("Error evaluating rule", logging.Node(nodeName), logging.Err(err))
+			metrics.PollErrors.WithLabelValues(nodeName, "rule").Inc()
+			continue
+		}
+		metrics.RuleMatched.WithLabelValues(nodeName, rule.Name).Set(boolToFloat(matched))
+		if !matched {
+			continue
+		}
+		b := breach{rule: rule.Name, value: 1}
+		c.logBreach(nodeName, b)
+		breaches = append(breaches, b)
+	}
+
+	return breaches
+}
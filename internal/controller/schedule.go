@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"log/slog"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+// resolveSchedule selects the first schedule active at the current time
+// and reports changes in the logs and metrics.
+func (c *Controller) resolveSchedule() {
+	var active *config.Schedule
+	now := c.now()
+	for i := range c.config.Schedules {
+		s := &c.config.Schedules[i]
+		ok, err := s.Active(now)
+		if err != nil {
+			// Config.Validate rejects schedules that cannot be evaluated.
+			c.logger.Error("Error evaluating schedule", slog.String("schedule", s.Name), logging.Err(err))
+			continue
+		}
+		if ok {
+			active = s
+			break
+		}
+	}
+
+	if scheduleName(active) != scheduleName(c.schedule) {
+		c.logger.Info("Threshold schedule changed", slog.String("from", scheduleName(c.schedule)), slog.String("to", scheduleName(active)))
+	}
+	c.schedule = active
+
+	metrics.ActiveSchedule.WithLabelValues(config.DefaultScheduleName).Set(boolToFloat(active == nil))
+	for i := range c.config.Schedules {
+		s := &c.config.Schedules[i]
+		metrics.ActiveSchedule.WithLabelValues(s.Name).Set(boolToFloat(s == active))
+	}
+}
+
+// thresholds returns the thresholds in effect under the active schedule.
+func (c *Controller) thresholds() config.PSIThresholds {
+	if c.schedule == nil {
+		return c.config.Thresholds
+	}
+	return c.schedule.Apply(c.config.Thresholds)
+}
+
+// logBreach logs a breach along with the active schedule, if any.
+func (c *Controller) logBreach(nodeName string, b breach) {
+	args := append([]any{logging.Node(nodeName)}, b.attrs()...)
+	if c.schedule != nil {
+		args = append(args, slog.String("schedule", c.schedule.Name))
+	}
+	c.logger.Info("Threshold exceeded", args...)
+}
+
+// scheduleSuffix annotates Event messages with the active schedule, if any.
+func (c *Controller) scheduleSuffix() string {
+	if c.schedule == nil {
+		return ""
+	}
+	return " [schedule " + c.schedule.Name + "]"
+}
+
+func scheduleName(s *config.Schedule) string {
+	if s == nil {
+		return config.DefaultScheduleName
+	}
+	return s.Name
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+// evaluateSignals checks the non-PSI signals configured in
+// Thresholds.Signals: kubelet resource stats and node conditions.
+func (c *Controller) evaluateSignals(nodePSI *psi.NodePSI, conditions map[string]bool, nodeName string) []breach {
+	signals := c.config.Thresholds.Signals
+	var breaches []breach
+
+	if minBytes, err := signals.MemoryAvailableBelowBytes(); err == nil && minBytes > 0 && nodePSI.MemoryAvailableBytes != nil {
+		available := float64(*nodePSI.MemoryAvailableBytes)
+		if available < float64(minBytes) {
+			b := breach{signal: "memory.availableBytes", value: available, threshold: float64(minBytes), below: true}
+			c.logBreach(nodeName, b)
+			breaches = append(breaches, b)
+		}
+	}
+
+	if pct := signals.FSAvailableBelowPercent; pct > 0 && nodePSI.FSAvailableBytes != nil && nodePSI.FSCapacityBytes != nil && *nodePSI.FSCapacityBytes > 0 {
+		available := float64(*nodePSI.FSAvailableBytes) / float64(*nodePSI.FSCapacityBytes) * 100
+		if available < pct {
+			b := breach{signal: "fs.availablePercent", value: available, threshold: pct, below: true}
+			c.logBreach(nodeName, b)
+			breaches = append(breaches, b)
+		}
+	}
+
+	for _, cond := range signals.TaintOnConditions {
+		if conditions[cond] {
+			b := breach{signal: "condition." + cond, value: 1, condition: true}
+			c.logBreach(nodeName, b)
+			breaches = append(breaches, b)
+		}
+	}
+
+	return breaches
+}
+
+// holdingCondition returns the first HoldOnConditions entry that is True
+// on the node, or "" if none is.
+func (c *Controller) holdingCondition(state *nodeState) string {
+	for _, cond := range c.config.Thresholds.Signals.HoldOnConditions {
+		if state.conditions[cond] {
+			return cond
+		}
+	}
+	return ""
+}
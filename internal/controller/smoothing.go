@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"math"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+// smooth exports the raw sample and returns a copy in which the resources
+// with a configured half-life hold their exponential moving average.
+// Resources missing from raw are neither exported nor averaged: their
+// average is kept until fresh data returns.
+func (c *Controller) smooth(nodeName string, state *nodeState, raw *psi.NodePSI) *psi.NodePSI {
+	halfLives := map[string]time.Duration{
+		psi.ResourceCPU:    c.config.Smoothing.CPU,
+		psi.ResourceMemory: c.config.Smoothing.Memory,
+		psi.ResourceIO:     c.config.Smoothing.IO,
+	}
+
+	smoothed := *raw
+	// next is the state kept for the following poll.
+	next := smoothed
+	next.Missing = nil
+	now := c.now()
+	prev := state.smoothed
+	if state.smoothedAt == nil {
+		state.smoothedAt = make(map[string]time.Time)
+	}
+	enabled := false
+	for _, r := range []string{psi.ResourceCPU, psi.ResourceMemory, psi.ResourceIO} {
+		halfLife := halfLives[r]
+		if raw.IsMissing(r) {
+			if halfLife > 0 && prev != nil && !prev.IsMissing(r) {
+				*next.ForResource(r) = *prev.ForResource(r)
+			} else {
+				next.Missing = append(next.Missing, r)
+			}
+			continue
+		}
+		exportPressure(nodeName, r, "raw", *raw.ForResource(r))
+
+		if halfLife <= 0 {
+			continue
+		}
+		enabled = true
+		current := smoothed.ForResource(r)
+		if prev != nil && !prev.IsMissing(r) {
+			alpha := 1 - math.Exp2(-now.Sub(state.smoothedAt[r]).Seconds()/halfLife.Seconds())
+			p := prev.ForResource(r)
+			current.Some = ewma(p.Some, current.Some, alpha)
+			current.Full = ewma(p.Full, current.Full, alpha)
+		}
+		*next.ForResource(r) = *current
+		state.smoothedAt[r] = now
+		exportPressure(nodeName, r, "smoothed", *current)
+	}
+
+	if !enabled {
+		return raw
+	}
+	state.smoothed = &next
+	return &smoothed
+}
+
+// ewma moves prev towards current by alpha.
+func ewma(prev, current psi.Averages, alpha float64) psi.Averages {
+	return psi.Averages{
+		Avg10:  prev.Avg10 + alpha*(current.Avg10-prev.Avg10),
+		Avg60:  prev.Avg60 + alpha*(current.Avg60-prev.Avg60),
+		Avg300: prev.Avg300 + alpha*(current.Avg300-prev.Avg300),
+		Total:  current.Total,
+	}
+}
+
+func exportPressure(nodeName, resource, kind string, p psi.Pressure) {
+	for _, k := range []string{"some", "full"} {
+		for _, w := range []string{"avg10", "avg60", "avg300"} {
+			v, _ := p.Value(k, w)
+			metrics.PSIPressure.WithLabelValues(nodeName, resource, k, w, kind).Set(v)
+		}
+	}
+}
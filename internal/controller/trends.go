@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+// risePolls returns the number of samples a rise trigger spans.
+func risePolls(r config.RiseTrigger) int {
+	return max(r.Polls, 2)
+}
+
+// recordHistory appends the sample to the node's history, keeping only as
+// many samples as the trend triggers need.
+func (c *Controller) recordHistory(state *nodeState, nodePSI *psi.NodePSI) {
+	if c.historySize == 0 {
+		return
+	}
+	state.history = append(state.history, nodePSI)
+	if extra := len(state.history) - c.historySize; extra > 0 {
+		state.history = state.history[extra:]
+	}
+}
+
+// evaluateTrends checks the rise and divergence triggers configured in
+// Thresholds.Trends. history holds the recent samples, newest last.
+func (c *Controller) evaluateTrends(nodePSI *psi.NodePSI, history []*psi.NodePSI, nodeName string) []breach {
+	trends := c.config.Thresholds.Trends
+	var breaches []breach
+
+	for _, r := range trends.Rise {
+		polls := risePolls(r)
+		if len(history) < polls {
+			continue
+		}
+		window := history[len(history)-polls:]
+		resource := strings.SplitN(r.Metric, ".", 2)[0]
+		if window[0].IsMissing(resource) || window[len(window)-1].IsMissing(resource) {
+			continue
+		}
+		first, _ := window[0].Value(r.Metric)
+		last, _ := window[len(window)-1].Value(r.Metric)
+		if rise := last - first; rise > r.RiseBy {
+			b := breach{signal: r.Metric + ".rise", value: rise, threshold: r.RiseBy}
+			c.logBreach(nodeName, b)
+			breaches = append(breaches, b)
+		}
+	}
+
+	for _, d := range trends.Divergence {
+		avg10, ok := nodePSI.Value(d.Pressure + ".avg10")
+		if !ok {
+			continue
+		}
+		avg300, _ := nodePSI.Value(d.Pressure + ".avg300")
+		// An avg300 of 0 is an idle node, not a baseline: any avg10 would
+		// exceed it.
+		if avg300 <= 0 || avg10 < d.MinAvg10 || avg10 <= d.Factor*avg300 {
+			continue
+		}
+		b := breach{signal: d.Pressure + ".avg10/avg300", value: avg10, threshold: d.Factor * avg300}
+		c.logBreach(nodeName, b)
+		breaches = append(breaches, b)
+	}
+
+	return breaches
+}
@@ -2,14 +2,25 @@ package kubernetes
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
 )
 
 // KubeClientInterface defines the methods our controller needs to interact with Kubernetes.
@@ -17,18 +28,62 @@ type KubeClientInterface interface {
 	ApplyTaint(ctx context.Context, nodeName, taintKey, taintValue, taintEffect string) error
 	RemoveTaint(ctx context.Context, nodeName, taintKey, taintEffect string) error
 	HasTaint(ctx context.Context, nodeName, taintKey, taintEffect string) (bool, error)
-	ListNodeNames(ctx context.Context, labelSelector string) ([]string, error)
+	ListNodes(ctx context.Context, labelSelector string) ([]NodeInfo, error)
+	RecordNodeEvent(ctx context.Context, nodeName, eventType, reason, message string) error
+	SetNodeAnnotation(ctx context.Context, nodeName, key, value string) error
+	RemoveNodeAnnotation(ctx context.Context, nodeName, key string) error
+	PatchNodeAnnotations(ctx context.Context, nodeName string, annotations map[string]*string) error
+	GetPodLabels(ctx context.Context, namespace, name string) (map[string]string, error)
+	EvictPod(ctx context.Context, namespace, name string) error
+	SetNodeCondition(ctx context.Context, nodeName, conditionType string, status bool, reason, message string) error
+	SetNodeLabel(ctx context.Context, nodeName, key, value string) error
+	GetConfigMapValue(ctx context.Context, namespace, name, key string) (string, error)
+}
+
+// ErrEvictionBlocked is returned by EvictPod when the API server refuses an
+// eviction, typically because it would violate a PodDisruptionBudget.
+var ErrEvictionBlocked = errors.New("eviction blocked")
+
+// eventComponent is the source component reported on Events created by the controller.
+const eventComponent = "kube-dethrottler"
+
+// Taints that cluster-autoscaler and Karpenter place on nodes they are
+// about to remove.
+const (
+	ToBeDeletedTaint        = "ToBeDeletedByClusterAutoscaler"
+	KarpenterDisruptedTaint = "karpenter.sh/disrupted"
+)
+
+// NodeInfo is the subset of a Node object used by the controller.
+type NodeInfo struct {
+	// Conditions maps each node condition type to whether its status is True.
+	Conditions  map[string]bool
+	Labels      map[string]string
+	Annotations map[string]string
+	// Allocatable holds the node's allocatable CPU in cores and memory in
+	// bytes, keyed "cpu" and "memory".
+	Allocatable map[string]float64
+	Name        string
+	// NotReady is set when the node reports a Ready condition that is not True.
+	NotReady bool
+	// Unschedulable is set when the node is cordoned.
+	Unschedulable bool
+	// Deleting is set when the node has a deletion timestamp or carries a
+	// cluster-autoscaler or Karpenter removal taint.
+	Deleting bool
+	Taints   []corev1.Taint
 }
 
 // Client provides methods to interact with the Kubernetes API.
 type Client struct {
 	clientset kubernetes.Interface
+	logger    *slog.Logger
 }
 
 var _ KubeClientInterface = (*Client)(nil)
 
-// NewClient creates a new Kubernetes API client.
-func NewClient(kubeconfigPath string) (*Client, error) {
+// NewClient creates a new Kubernetes API client logging to logger.
+func NewClient(kubeconfigPath string, logger *slog.Logger) (*Client, error) {
 	cfg, err := buildConfig(kubeconfigPath)
 	if err != nil {
 		return nil, err
@@ -38,7 +93,15 @@ func NewClient(kubeconfigPath string) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
 	}
-	return &Client{clientset: clientset}, nil
+	return &Client{clientset: clientset, logger: logger}, nil
+}
+
+// log returns the client's logger, or the default logger if none was set.
+func (c *Client) log() *slog.Logger {
+	if c.logger == nil {
+		return slog.Default()
+	}
+	return c.logger
 }
 
 // Clientset returns the underlying kubernetes.Interface for use by other
@@ -67,8 +130,8 @@ func buildConfig(kubeconfigPath string) (*rest.Config, error) {
 	return cfg, nil
 }
 
-// ListNodeNames returns the names of all nodes matching the given label selector.
-func (c *Client) ListNodeNames(ctx context.Context, labelSelector string) ([]string, error) {
+// ListNodes returns information about all nodes matching the given label selector.
+func (c *Client) ListNodes(ctx context.Context, labelSelector string) ([]NodeInfo, error) {
 	opts := metav1.ListOptions{}
 	if labelSelector != "" {
 		opts.LabelSelector = labelSelector
@@ -79,11 +142,39 @@ func (c *Client) ListNodeNames(ctx context.Context, labelSelector string) ([]str
 		return nil, fmt.Errorf("failed to list nodes: %w", err)
 	}
 
-	names := make([]string, 0, len(nodes.Items))
+	infos := make([]NodeInfo, 0, len(nodes.Items))
 	for i := range nodes.Items {
-		names = append(names, nodes.Items[i].Name)
+		infos = append(infos, nodeInfo(&nodes.Items[i]))
+	}
+	return infos, nil
+}
+
+func nodeInfo(node *corev1.Node) NodeInfo {
+	info := NodeInfo{
+		Name:        node.Name,
+		Labels:      node.Labels,
+		Annotations: node.Annotations,
+		Allocatable: map[string]float64{
+			string(corev1.ResourceCPU):    node.Status.Allocatable.Cpu().AsApproximateFloat64(),
+			string(corev1.ResourceMemory): node.Status.Allocatable.Memory().AsApproximateFloat64(),
+		},
+		Conditions: make(map[string]bool, len(node.Status.Conditions)),
+	}
+	for _, cond := range node.Status.Conditions {
+		info.Conditions[string(cond.Type)] = cond.Status == corev1.ConditionTrue
+		if cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue {
+			info.NotReady = true
+		}
 	}
-	return names, nil
+	info.Unschedulable = node.Spec.Unschedulable
+	info.Taints = node.Spec.Taints
+	info.Deleting = node.DeletionTimestamp != nil
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == ToBeDeletedTaint || taint.Key == KarpenterDisruptedTaint {
+			info.Deleting = true
+		}
+	}
+	return info
 }
 
 // ApplyTaint adds a taint to a node.
@@ -105,7 +196,7 @@ func (c *Client) ApplyTaint(ctx context.Context, nodeName, taintKey, taintValue,
 		Value:  taintValue,
 		Effect: corev1.TaintEffect(effect),
 	})
-	log.Printf("Adding taint '%s' to node: %v", taintKey, nodeName)
+	c.log().Info("Adding taint", logging.Node(nodeName), logging.Action("taint "+taintKey+":"+effect))
 
 	return updateNodeWithTaints(ctx, c.clientset, nodeName, taints)
 }
@@ -131,7 +222,7 @@ func (c *Client) RemoveTaint(ctx context.Context, nodeName, taintKey, taintEffec
 		return nil
 	}
 
-	log.Printf("Removing taint '%s' from node: %v", taintKey, nodeName)
+	c.log().Info("Removing taint", logging.Node(nodeName), logging.Action("taint "+taintKey+":"+taintEffect))
 	return updateNodeWithTaints(ctx, c.clientset, nodeName, newTaints)
 }
 
@@ -166,3 +257,181 @@ func (c *Client) HasTaint(ctx context.Context, nodeName, taintKey, taintEffect s
 	}
 	return false, nil
 }
+
+// RecordNodeEvent creates an Event attached to the given node. Node events
+// live in the "default" namespace and use the node name as UID, matching
+// the kubelet so that `kubectl describe node` shows them.
+func (c *Client) RecordNodeEvent(ctx context.Context, nodeName, eventType, reason, message string) error {
+	now := metav1.NewTime(time.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", nodeName, now.UnixNano()),
+			Namespace: metav1.NamespaceDefault,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "Node",
+			Name: nodeName,
+			UID:  types.UID(nodeName),
+		},
+		Reason:              reason,
+		Message:             message,
+		Type:                eventType,
+		Source:              corev1.EventSource{Component: eventComponent},
+		ReportingController: eventComponent,
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+
+	_, err := c.clientset.CoreV1().Events(metav1.NamespaceDefault).Create(ctx, event, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create event for node %s: %w", nodeName, err)
+	}
+	return nil
+}
+
+// SetNodeAnnotation sets an annotation on a node using a merge patch.
+func (c *Client) SetNodeAnnotation(ctx context.Context, nodeName, key, value string) error {
+	return patchNodeMetadata(ctx, c.clientset, nodeName, "annotation", map[string]*string{key: &value})
+}
+
+// RemoveNodeAnnotation removes an annotation from a node using a merge patch.
+// Removing an annotation that is not present is not an error.
+func (c *Client) RemoveNodeAnnotation(ctx context.Context, nodeName, key string) error {
+	return patchNodeMetadata(ctx, c.clientset, nodeName, "annotation", map[string]*string{key: nil})
+}
+
+// PatchNodeAnnotations sets the given annotations on a node, and removes
+// those whose value is nil, in a single merge patch.
+func (c *Client) PatchNodeAnnotations(ctx context.Context, nodeName string, annotations map[string]*string) error {
+	return patchNodeMetadata(ctx, c.clientset, nodeName, "annotation", annotations)
+}
+
+// SetNodeLabel sets a label on a node using a merge patch.
+func (c *Client) SetNodeLabel(ctx context.Context, nodeName, key, value string) error {
+	return patchNodeMetadata(ctx, c.clientset, nodeName, "label", map[string]*string{key: &value})
+}
+
+// patchNodeMetadata sets, or removes when their value is nil, labels or
+// annotations on a node. kind is "label" or "annotation".
+func patchNodeMetadata(ctx context.Context, clientset kubernetes.Interface, nodeName, kind string, values map[string]*string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			kind + "s": values,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build %s patch: %w", kind, err)
+	}
+
+	_, err = clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch %ss %s on node %s: %w", kind, strings.Join(slices.Sorted(maps.Keys(values)), ", "), nodeName, err)
+	}
+	return nil
+}
+
+// GetConfigMapValue returns the value of a key in a ConfigMap, or "" if the
+// ConfigMap or the key does not exist.
+func (c *Client) GetConfigMapValue(ctx context.Context, namespace, name, key string) (string, error) {
+	cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get configmap %s/%s: %w", namespace, name, err)
+	}
+	return cm.Data[key], nil
+}
+
+// SetConfigMapValue sets a key in a ConfigMap, creating the ConfigMap if
+// it does not exist.
+func (c *Client) SetConfigMapValue(ctx context.Context, namespace, name, key, value string) error {
+	patch, err := json.Marshal(map[string]any{"data": map[string]string{key: value}})
+	if err != nil {
+		return fmt.Errorf("failed to build configmap patch: %w", err)
+	}
+	configMaps := c.clientset.CoreV1().ConfigMaps(namespace)
+	_, err = configMaps.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       map[string]string{key: value},
+		}
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set %s in configmap %s/%s: %w", key, namespace, name, err)
+	}
+	return nil
+}
+
+// SetNodeCondition sets a condition in the node's status. The condition's
+// lastTransitionTime only changes when its status does; lastHeartbeatTime
+// is refreshed on every call.
+func (c *Client) SetNodeCondition(ctx context.Context, nodeName, conditionType string, status bool, reason, message string) error {
+	node, err := c.clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+
+	now := metav1.NewTime(time.Now())
+	condition := corev1.NodeCondition{
+		Type:               corev1.NodeConditionType(conditionType),
+		Status:             corev1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	}
+	if status {
+		condition.Status = corev1.ConditionTrue
+	}
+	for _, existing := range node.Status.Conditions {
+		if existing.Type == condition.Type && existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"conditions": []corev1.NodeCondition{condition},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build condition patch: %w", err)
+	}
+
+	// Conditions merge by type, so other conditions are left untouched.
+	_, err = c.clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		return fmt.Errorf("failed to patch condition %s on node %s: %w", conditionType, nodeName, err)
+	}
+	return nil
+}
+
+// GetPodLabels returns the labels of a pod.
+func (c *Client) GetPodLabels(ctx context.Context, namespace, name string) (map[string]string, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+	}
+	return pod.Labels, nil
+}
+
+// EvictPod evicts a pod through the Eviction API, which honours
+// PodDisruptionBudgets. A refused eviction wraps ErrEvictionBlocked.
+func (c *Client) EvictPod(ctx context.Context, namespace, name string) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	err := c.clientset.CoreV1().Pods(namespace).EvictV1(ctx, eviction)
+	if apierrors.IsTooManyRequests(err) {
+		return fmt.Errorf("%w: pod %s/%s: %v", ErrEvictionBlocked, namespace, name, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to evict pod %s/%s: %w", namespace, name, err)
+	}
+	c.log().Info("Evicted pod", logging.Pod(namespace, name))
+	return nil
+}
@@ -3,11 +3,16 @@ package kubernetes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -59,7 +64,7 @@ users:
 		t.Fatalf("Failed to write to temp kubeconfig file: %v", err)
 	}
 
-	client, err := NewClient(tempFile.Name())
+	client, err := NewClient(tempFile.Name(), nil)
 	if err != nil {
 		t.Fatalf("NewClient() with kubeconfig error = %v", err)
 	}
@@ -72,7 +77,7 @@ users:
 }
 
 func TestNewClient_NoConfig(t *testing.T) {
-	_, err := NewClient("") // No in-cluster, no kubeconfig path
+	_, err := NewClient("", nil) // No in-cluster, no kubeconfig path
 	if err == nil {
 		t.Error("NewClient() with no config, error = nil, wantErr true")
 	}
@@ -290,3 +295,326 @@ func TestHasTaint(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordNodeEvent(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	k8sClient := &Client{clientset: client}
+
+	err := k8sClient.RecordNodeEvent(ctx, "test-node", corev1.EventTypeWarning, "PSIDataUnavailable", "no PSI data")
+	if err != nil {
+		t.Fatalf("RecordNodeEvent() error = %v", err)
+	}
+
+	events, err := client.CoreV1().Events(metav1.NamespaceDefault).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(events.Items) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events.Items))
+	}
+	ev := events.Items[0]
+	if ev.InvolvedObject.Kind != "Node" || ev.InvolvedObject.Name != "test-node" {
+		t.Errorf("Unexpected involved object: %+v", ev.InvolvedObject)
+	}
+	if ev.Type != corev1.EventTypeWarning || ev.Reason != "PSIDataUnavailable" {
+		t.Errorf("Unexpected event type/reason: %s/%s", ev.Type, ev.Reason)
+	}
+}
+
+func TestSetAndRemoveNodeAnnotation(t *testing.T) {
+	ctx := context.Background()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-node",
+			Annotations: map[string]string{"other": "keep"},
+		},
+	}
+	client := fake.NewSimpleClientset(node)
+	k8sClient := &Client{clientset: client}
+
+	if err := k8sClient.SetNodeAnnotation(ctx, "test-node", "kube-dethrottler.io/top-pods", "default/noisy"); err != nil {
+		t.Fatalf("SetNodeAnnotation() error = %v", err)
+	}
+	got, err := client.CoreV1().Nodes().Get(ctx, "test-node", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+	if got.Annotations["kube-dethrottler.io/top-pods"] != "default/noisy" {
+		t.Errorf("Annotation not set, got %v", got.Annotations)
+	}
+	if got.Annotations["other"] != "keep" {
+		t.Errorf("Unrelated annotation was not preserved, got %v", got.Annotations)
+	}
+
+	if err := k8sClient.RemoveNodeAnnotation(ctx, "test-node", "kube-dethrottler.io/top-pods"); err != nil {
+		t.Fatalf("RemoveNodeAnnotation() error = %v", err)
+	}
+	got, err = client.CoreV1().Nodes().Get(ctx, "test-node", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+	if _, exists := got.Annotations["kube-dethrottler.io/top-pods"]; exists {
+		t.Errorf("Annotation not removed, got %v", got.Annotations)
+	}
+}
+
+func TestPatchNodeAnnotations(t *testing.T) {
+	ctx := context.Background()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-node",
+			Annotations: map[string]string{"other": "keep", "stale": "x"},
+		},
+	}
+	client := fake.NewSimpleClientset(node)
+	k8sClient := &Client{clientset: client}
+
+	value := "true"
+	if err := k8sClient.PatchNodeAnnotations(ctx, "test-node", map[string]*string{"a": &value, "b": &value, "stale": nil}); err != nil {
+		t.Fatalf("PatchNodeAnnotations() error = %v", err)
+	}
+	got, err := client.CoreV1().Nodes().Get(ctx, "test-node", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+	want := map[string]string{"other": "keep", "a": "true", "b": "true"}
+	if !maps.Equal(got.Annotations, want) {
+		t.Errorf("Annotations = %v, want %v", got.Annotations, want)
+	}
+	patches := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "patch" {
+			patches++
+		}
+	}
+	if patches != 1 {
+		t.Errorf("Expected a single patch, got %d", patches)
+	}
+}
+
+func TestSetNodeLabel(t *testing.T) {
+	ctx := context.Background()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{"pool": "a", "kube-dethrottler.io/pressure": "none"},
+		},
+	}
+	client := fake.NewSimpleClientset(node)
+	k8sClient := &Client{clientset: client}
+
+	if err := k8sClient.SetNodeLabel(ctx, "test-node", "kube-dethrottler.io/pressure", "cpu"); err != nil {
+		t.Fatalf("SetNodeLabel() error = %v", err)
+	}
+	got, err := client.CoreV1().Nodes().Get(ctx, "test-node", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+	if got.Labels["kube-dethrottler.io/pressure"] != "cpu" {
+		t.Errorf("Label not set, got %v", got.Labels)
+	}
+	if got.Labels["pool"] != "a" {
+		t.Errorf("Unrelated label was not preserved, got %v", got.Labels)
+	}
+
+	if err := k8sClient.SetNodeLabel(ctx, "missing", "kube-dethrottler.io/pressure", "cpu"); err == nil {
+		t.Error("SetNodeLabel() on a missing node should fail")
+	}
+}
+
+func TestEvictPod(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "noisy", Namespace: "batch"}}
+
+	client := fake.NewSimpleClientset(pod)
+	k8sClient := &Client{clientset: client}
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		return true, nil, nil
+	})
+
+	if err := k8sClient.EvictPod(ctx, "batch", "noisy"); err != nil {
+		t.Fatalf("EvictPod() error = %v", err)
+	}
+}
+
+func TestEvictPod_BlockedByPDB(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "noisy", Namespace: "batch"}}
+
+	client := fake.NewSimpleClientset(pod)
+	k8sClient := &Client{clientset: client}
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+	})
+
+	err := k8sClient.EvictPod(ctx, "batch", "noisy")
+	if !errors.Is(err, ErrEvictionBlocked) {
+		t.Fatalf("EvictPod() error = %v, want ErrEvictionBlocked", err)
+	}
+}
+
+func TestListNodes(t *testing.T) {
+	ctx := context.Background()
+	worker := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{"role": "worker"}},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+	control := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "control", Labels: map[string]string{"role": "control"}}}
+
+	client := fake.NewSimpleClientset(worker, control)
+	k8sClient := &Client{clientset: client}
+
+	nodes, err := k8sClient.ListNodes(ctx, "role=worker")
+	if err != nil {
+		t.Fatalf("ListNodes() error = %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Name != "worker" {
+		t.Fatalf("ListNodes() = %+v, want only worker", nodes)
+	}
+	if !nodes[0].Conditions["Ready"] || nodes[0].Conditions["DiskPressure"] {
+		t.Errorf("Unexpected conditions: %v", nodes[0].Conditions)
+	}
+	if nodes[0].Labels["role"] != "worker" {
+		t.Errorf("Unexpected labels: %v", nodes[0].Labels)
+	}
+	if nodes[0].Allocatable["cpu"] != 4 || nodes[0].Allocatable["memory"] != 8<<30 {
+		t.Errorf("Unexpected allocatable: %v", nodes[0].Allocatable)
+	}
+
+	all, err := k8sClient.ListNodes(ctx, "")
+	if err != nil {
+		t.Fatalf("ListNodes() error = %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("ListNodes() = %+v, want 2 nodes without a selector", all)
+	}
+}
+
+func TestListNodes_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := metav1.Now()
+	nodes := []*corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready"},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "not-ready"},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionUnknown}}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cordoned"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "scaling-down"},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: ToBeDeletedTaint, Effect: corev1.TaintEffectNoSchedule}}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "deleted", DeletionTimestamp: &now, Finalizers: []string{"example.com/hold"}}},
+	}
+
+	client := fake.NewSimpleClientset(nodes[0], nodes[1], nodes[2], nodes[3], nodes[4])
+	k8sClient := &Client{clientset: client}
+
+	infos, err := k8sClient.ListNodes(ctx, "")
+	if err != nil {
+		t.Fatalf("ListNodes() error = %v", err)
+	}
+
+	want := map[string]NodeInfo{
+		"ready":        {},
+		"not-ready":    {NotReady: true},
+		"cordoned":     {Unschedulable: true},
+		"scaling-down": {Deleting: true},
+		"deleted":      {Deleting: true},
+	}
+	for _, info := range infos {
+		w := want[info.Name]
+		if info.NotReady != w.NotReady || info.Unschedulable != w.Unschedulable || info.Deleting != w.Deleting {
+			t.Errorf("node %s: NotReady=%v Unschedulable=%v Deleting=%v, want %v %v %v",
+				info.Name, info.NotReady, info.Unschedulable, info.Deleting, w.NotReady, w.Unschedulable, w.Deleting)
+		}
+	}
+}
+
+func TestSetNodeCondition(t *testing.T) {
+	ctx := context.Background()
+	earlier := metav1.NewTime(metav1.Now().Add(-time.Hour).Truncate(time.Second))
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			{Type: "PSIPressure", Status: corev1.ConditionFalse, LastTransitionTime: earlier},
+		}},
+	}
+	client := fake.NewSimpleClientset(node)
+	k8sClient := &Client{clientset: client}
+
+	condition := func() corev1.NodeCondition {
+		t.Helper()
+		n, err := client.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if len(n.Status.Conditions) != 2 {
+			t.Fatalf("conditions = %+v, want Ready and PSIPressure", n.Status.Conditions)
+		}
+		for _, c := range n.Status.Conditions {
+			if c.Type == "PSIPressure" {
+				return c
+			}
+		}
+		t.Fatal("PSIPressure condition not found")
+		return corev1.NodeCondition{}
+	}
+
+	// Same status: the transition time is preserved.
+	if err := k8sClient.SetNodeCondition(ctx, "node-1", "PSIPressure", false, "PressureNormal", "ok"); err != nil {
+		t.Fatalf("SetNodeCondition() error = %v", err)
+	}
+	if c := condition(); !c.LastTransitionTime.Equal(&earlier) || c.Reason != "PressureNormal" {
+		t.Errorf("condition = %+v, want reason updated and transition time kept", c)
+	}
+
+	// New status: the transition time moves.
+	if err := k8sClient.SetNodeCondition(ctx, "node-1", "PSIPressure", true, "PressureHigh", "cpu.some.avg10 50.00 > 25.00"); err != nil {
+		t.Fatalf("SetNodeCondition() error = %v", err)
+	}
+	c := condition()
+	if c.Status != corev1.ConditionTrue || c.Message != "cpu.some.avg10 50.00 > 25.00" {
+		t.Errorf("condition = %+v, want True with message", c)
+	}
+	if !c.LastTransitionTime.After(earlier.Time) {
+		t.Errorf("LastTransitionTime = %v, want after %v", c.LastTransitionTime, earlier)
+	}
+}
+
+func TestConfigMapValue(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := &Client{clientset: fake.NewSimpleClientset()}
+
+	if v, err := k8sClient.GetConfigMapValue(ctx, "kube-system", "kube-dethrottler-pause", "paused"); err != nil || v != "" {
+		t.Fatalf("GetConfigMapValue() on a missing configmap = %q, %v; want empty", v, err)
+	}
+	for _, value := range []string{"true", "false"} {
+		if err := k8sClient.SetConfigMapValue(ctx, "kube-system", "kube-dethrottler-pause", "paused", value); err != nil {
+			t.Fatalf("SetConfigMapValue(%s) error = %v", value, err)
+		}
+		if v, err := k8sClient.GetConfigMapValue(ctx, "kube-system", "kube-dethrottler-pause", "paused"); err != nil || v != value {
+			t.Errorf("GetConfigMapValue() = %q, %v; want %q", v, err, value)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package logging builds the structured logger and defines the attributes
+// shared by controller and client messages, so that log pipelines can
+// query them consistently.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Attribute keys used across the project.
+const (
+	KeyNode      = "node"
+	KeyResource  = "resource"
+	KeyType      = "type"
+	KeyWindow    = "window"
+	KeyValue     = "value"
+	KeyThreshold = "threshold"
+	KeyAction    = "action"
+	KeyError     = "error"
+	KeyPod       = "pod"
+)
+
+// New returns a logger writing to w at the given level ("debug", "info",
+// "warn" or "error") in the given format (FormatText or FormatJSON).
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format: %s. Must be one of: text, json", format)
+	}
+}
+
+// ParseLevel parses a level name; an empty name is "info".
+func ParseLevel(level string) (slog.Level, error) {
+	var lvl slog.Level
+	if level == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("invalid log level: %s. Must be one of: debug, info, warn, error", level)
+	}
+	return lvl, nil
+}
+
+// Node returns the node attribute.
+func Node(name string) slog.Attr {
+	return slog.String(KeyNode, name)
+}
+
+// Action returns the action attribute, e.g.
+// "taint kube-dethrottler/high-load:NoSchedule".
+func Action(action string) slog.Attr {
+	return slog.String(KeyAction, action)
+}
+
+// Resource returns the resource attribute.
+func Resource(resource string) slog.Attr {
+	return slog.String(KeyResource, resource)
+}
+
+// Value returns the value attribute.
+func Value(value float64) slog.Attr {
+	return slog.Float64(KeyValue, value)
+}
+
+// Pod returns the pod attribute as namespace/name.
+func Pod(namespace, name string) slog.Attr {
+	return slog.String(KeyPod, namespace+"/"+name)
+}
+
+// Type returns the type attribute, e.g. a PSI type or an event type.
+func Type(typ string) slog.Attr {
+	return slog.String(KeyType, typ)
+}
+
+// Err returns the error attribute.
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
+
+// Threshold returns the attributes of a value compared with a threshold:
+// resource, type and window name the PSI series, e.g. cpu, some and avg10,
+// and are left out when empty.
+func Threshold(resource, typ, window string, value, threshold float64) []any {
+	attrs := make([]any, 0, 5)
+	for _, a := range []struct{ key, value string }{{KeyResource, resource}, {KeyType, typ}, {KeyWindow, window}} {
+		if a.value != "" {
+			attrs = append(attrs, slog.String(a.key, a.value))
+		}
+	}
+	return append(attrs, slog.Float64(KeyValue, value), slog.Float64(KeyThreshold, threshold))
+}
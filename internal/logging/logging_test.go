@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		format  string
+		wantErr string
+	}{
+		{name: "defaults", level: "", format: ""},
+		{name: "json debug", level: "debug", format: "json"},
+		{name: "upper case", level: "WARN", format: "TEXT"},
+		{name: "invalid level", level: "loud", format: "text", wantErr: "invalid log level"},
+		{name: "invalid format", level: "info", format: "xml", wantErr: "invalid log format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&bytes.Buffer{}, tt.level, tt.format)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("New() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("New() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_JSONAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("hidden")
+	args := append([]any{Node("node-1"), Action("taint")}, Threshold("cpu", "some", "avg10", 50, 25)...)
+	logger.Info("Threshold exceeded", args...)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want the debug message filtered out", len(lines))
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"node": "node-1", "action": "taint", "resource": "cpu", "type": "some",
+		"window": "avg10", "value": 50.0, "threshold": 25.0,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestThreshold_OmitsEmpty(t *testing.T) {
+	if got := Threshold("", "", "", 1, 2); len(got) != 2 {
+		t.Errorf("Threshold() = %v, want only value and threshold", got)
+	}
+}
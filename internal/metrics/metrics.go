@@ -6,11 +6,13 @@ import (
 )
 
 var (
-	// PSIPressure tracks current PSI pressure values per node.
+	// PSIPressure tracks current PSI pressure values per node. The kind
+	// label is "raw" for polled values and "smoothed" for their moving
+	// average when smoothing is enabled for the resource.
 	PSIPressure = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "kube_dethrottler_psi_pressure",
 		Help: "Current PSI pressure value (percentage)",
-	}, []string{"node", "resource", "type", "window"})
+	}, []string{"node", "resource", "type", "window", "kind"})
 
 	// NodeTainted tracks whether a node is currently tainted.
 	NodeTainted = promauto.NewGaugeVec(prometheus.GaugeOpts{
@@ -30,9 +32,105 @@ var (
 		Help: "Whether a specific threshold is exceeded (1 = exceeded, 0 = normal)",
 	}, []string{"node", "resource", "type", "window"})
 
+	// RuleMatched tracks which rule expressions currently match.
+	RuleMatched = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_rule_matched",
+		Help: "Whether a rule expression matches (1 = matched, 0 = not matched)",
+	}, []string{"node", "rule"})
+
+	// OutlierBound tracks the value above which a node is an outlier in its pool.
+	OutlierBound = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_pool_outlier_bound",
+		Help: "PSI value (percentage) above which a node is considered an outlier in its pool",
+	}, []string{"pool", "metric"})
+
+	// ActiveSchedule tracks which threshold schedule is in effect.
+	ActiveSchedule = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_active_schedule",
+		Help: "Whether a threshold schedule is active (1 = active, 0 = inactive); \"default\" when no schedule applies",
+	}, []string{"schedule"})
+
+	// EffectiveCooldown tracks the cooldown in effect for each tainted node.
+	EffectiveCooldown = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_effective_cooldown_seconds",
+		Help: "Cooldown in effect for the node's taint, including backoff for repeatedly tainted nodes",
+	}, []string{"node"})
+
+	// NodeSkipped tracks nodes skipped because of their lifecycle state.
+	NodeSkipped = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_node_skipped",
+		Help: "Whether the node is skipped because it is NotReady, cordoned or being deleted (1 = skipped)",
+	}, []string{"node", "state"})
+
+	// PoolNodes tracks the number of nodes and tainted nodes per pool.
+	PoolNodes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_pool_nodes",
+		Help: "Number of monitored nodes per pool, by state (total or tainted)",
+	}, []string{"pool", "state"})
+
+	// PoolTaintedCapacity tracks the allocatable capacity withheld by pressure taints.
+	PoolTaintedCapacity = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_pool_tainted_capacity",
+		Help: "Allocatable capacity of pressure-tainted nodes per pool (cpu in cores, memory in bytes)",
+	}, []string{"pool", "resource"})
+
+	// PoolTaintedRatio tracks the fraction of a pool's nodes that are tainted.
+	PoolTaintedRatio = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_pool_tainted_ratio",
+		Help: "Fraction of the pool's monitored nodes that carry the pressure taint",
+	}, []string{"pool"})
+
+	// Paused is 1 while the pause switch suspends changes to nodes.
+	Paused = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_paused",
+		Help: "Whether the pause switch suspends all changes to nodes (1 = paused)",
+	})
+
 	// PollErrors tracks errors during node polling.
 	PollErrors = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "kube_dethrottler_poll_errors_total",
 		Help: "Total number of errors during PSI polling",
 	}, []string{"node", "reason"})
+
+	// PSIDataMissing tracks which resources had no PSI data (or stale data) in the last sample.
+	PSIDataMissing = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_psi_data_missing",
+		Help: "Whether PSI data for a resource was missing or stale in the last sample (1 = missing, 0 = present)",
+	}, []string{"node", "resource"})
+
+	// PSISampleAge tracks the age of the last kubelet PSI sample.
+	PSISampleAge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_psi_sample_age_seconds",
+		Help: "Age of the last PSI sample reported by the kubelet, in seconds",
+	}, []string{"node"})
+
+	// NodeUnknown tracks nodes whose pressure state cannot be determined.
+	NodeUnknown = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_node_unknown",
+		Help: "Whether the node's pressure state is unknown due to missing or stale PSI data (1 = unknown, 0 = known)",
+	}, []string{"node"})
+
+	// PSISamples tracks which source supplied each PSI sample.
+	PSISamples = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kube_dethrottler_psi_samples_total",
+		Help: "Total number of PSI samples obtained, by the source that supplied them",
+	}, []string{"node", "source"})
+
+	// PodPressure tracks the PSI of the top pressure-contributing pods on tainted nodes.
+	PodPressure = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_dethrottler_top_pod_pressure",
+		Help: "PSI pressure value (percentage) of the top pressure-contributing pods on tainted nodes",
+	}, []string{"node", "namespace", "pod", "resource"})
+
+	// Evictions tracks noisy-neighbour eviction attempts.
+	Evictions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kube_dethrottler_evictions_total",
+		Help: "Total number of noisy-neighbour pod eviction attempts",
+	}, []string{"node", "status"})
+
+	// Notifications tracks events delivered to notification sinks.
+	Notifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kube_dethrottler_notifications_total",
+		Help: "Total number of events handed to notification sinks, by result (sent, failed, dropped)",
+	}, []string{"sink", "result"})
 )
@@ -0,0 +1,225 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+// CloudEvents types emitted by the controller. The data of each type is
+// described by the matching *Data struct and only ever gains fields.
+const (
+	// TypePressureDetected is emitted when a node's evaluation first finds
+	// breached thresholds, whether or not actions follow. Data is
+	// NodePressureData.
+	TypePressureDetected = "io.kube-dethrottler.node.pressure.detected"
+	// TypeTainted is emitted once the pressure actions have been applied
+	// to a node. Data is NodePressureData.
+	TypeTainted = "io.kube-dethrottler.node.tainted"
+	// TypeUntainted is emitted once the pressure actions have been
+	// released. Data is NodeUntaintedData.
+	TypeUntainted = "io.kube-dethrottler.node.untainted"
+	// TypePollFailed is emitted when listing nodes or fetching a node's PSI
+	// fails. Data is PollFailedData.
+	TypePollFailed = "io.kube-dethrottler.poll.failed"
+)
+
+// CloudEvents HTTP content modes.
+const (
+	ModeBinary     = "binary"
+	ModeStructured = "structured"
+)
+
+// NodePressureData is the data of TypePressureDetected and TypeTainted
+// events. Resource is the resource of the first breach, the one that
+// exceeds its threshold the most, or "other". Actions names the applied
+// actions and is only set on TypeTainted.
+type NodePressureData struct {
+	Node         string   `json:"node"`
+	Resource     string   `json:"resource"`
+	Reason       string   `json:"reason"`
+	Actions      string   `json:"actions,omitempty"`
+	Breaches     []Breach `json:"breaches"`
+	TaintedNodes int      `json:"taintedNodes"`
+}
+
+// NodeUntaintedData is the data of TypeUntainted events. TaintedSeconds
+// is how long the actions were in place.
+type NodeUntaintedData struct {
+	Node           string  `json:"node"`
+	Reason         string  `json:"reason"`
+	Actions        string  `json:"actions"`
+	TaintedSeconds float64 `json:"taintedSeconds"`
+	TaintedNodes   int     `json:"taintedNodes"`
+}
+
+// PollFailedData is the data of TypePollFailed events. Stage is "list"
+// when listing nodes failed, in which case Node is empty, or "fetch" when
+// fetching the node's PSI failed.
+type PollFailedData struct {
+	Node  string `json:"node,omitempty"`
+	Stage string `json:"stage"`
+	Error string `json:"error"`
+}
+
+// CloudEvent is a CloudEvents 1.0 event as sent in structured mode.
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            any       `json:"data"`
+}
+
+// CloudEventsConfig configures a CloudEvents sink.
+type CloudEventsConfig struct {
+	URL string
+	// Mode is ModeBinary (the default) or ModeStructured.
+	Mode string
+	// Source is the CloudEvents source attribute, e.g. a URI naming the
+	// cluster.
+	Source       string
+	HeadersFile  string
+	Timeout      time.Duration
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+// CloudEvents posts events to an HTTP endpoint from a background goroutine
+// started with Run, one event per request.
+type CloudEvents struct {
+	client *http.Client
+	events chan CloudEvent
+	logger *slog.Logger
+	cfg    CloudEventsConfig
+}
+
+// NewCloudEvents creates a CloudEvents sink.
+func NewCloudEvents(cfg CloudEventsConfig, logger *slog.Logger) *CloudEvents {
+	if cfg.Mode == "" {
+		cfg.Mode = ModeBinary
+	}
+	return &CloudEvents{
+		client: &http.Client{Timeout: cfg.Timeout},
+		events: make(chan CloudEvent, queueSize),
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// Emit queues an event of the given type about subject, usually a node
+// name. It never blocks; events are dropped when the queue is full.
+func (c *CloudEvents) Emit(eventType, subject string, t time.Time, data any) {
+	e := CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              newEventID(),
+		Source:          c.cfg.Source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            t.UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+	select {
+	case c.events <- e:
+	default:
+		metrics.Notifications.WithLabelValues("cloudevents", "dropped").Inc()
+		c.logger.Warn("CloudEvents queue full, dropping event", logging.Type(eventType))
+	}
+}
+
+// Run delivers queued events until ctx is cancelled, then makes a single
+// delivery attempt for the events still queued.
+func (c *CloudEvents) Run(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			for {
+				select {
+				case e := <-c.events:
+					c.send(context.Background(), e, 0)
+				default:
+					return
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+		case e := <-c.events:
+			c.send(ctx, e, c.cfg.MaxRetries)
+		}
+	}
+}
+
+func (c *CloudEvents) send(ctx context.Context, e CloudEvent, retries int) {
+	if err := c.Deliver(ctx, e, retries); err != nil {
+		metrics.Notifications.WithLabelValues("cloudevents", "failed").Inc()
+		c.logger.Error("Failed to deliver CloudEvent", logging.Type(e.Type), slog.String("id", e.ID), logging.Err(err))
+		return
+	}
+	metrics.Notifications.WithLabelValues("cloudevents", "sent").Inc()
+}
+
+// Deliver posts the event, retrying like Webhook.Deliver.
+func (c *CloudEvents) Deliver(ctx context.Context, e CloudEvent, retries int) error {
+	return Retry(ctx, retries, c.cfg.RetryBackoff, func() error {
+		return c.post(ctx, e)
+	})
+}
+
+func (c *CloudEvents) post(ctx context.Context, e CloudEvent) error {
+	headers, err := ReadHeaders(c.cfg.HeadersFile)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPermanent, err)
+	}
+
+	var body []byte
+	contentType := "application/cloudevents+json"
+	if c.cfg.Mode == ModeStructured {
+		body, err = json.Marshal(e)
+	} else {
+		body, err = json.Marshal(e.Data)
+		contentType = e.DataContentType
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to encode event: %w", errPermanent, err)
+	}
+
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: failed to build request: %w", errPermanent, err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if c.cfg.Mode != ModeStructured {
+		req.Header.Set("ce-specversion", e.SpecVersion)
+		req.Header.Set("ce-id", e.ID)
+		req.Header.Set("ce-source", e.Source)
+		req.Header.Set("ce-type", e.Type)
+		req.Header.Set("ce-time", e.Time.Format(time.RFC3339Nano))
+		if e.Subject != "" {
+			req.Header.Set("ce-subject", e.Subject)
+		}
+	}
+	return Do(c.client, req)
+}
+
+// newEventID returns a random 128-bit hex event ID.
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
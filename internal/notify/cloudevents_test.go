@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCloudEvents_BinaryMode(t *testing.T) {
+	rec := &recorder{}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	sink := NewCloudEvents(CloudEventsConfig{URL: server.URL, Source: "//prod-cluster", Timeout: time.Second}, testLogger())
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sink.Emit(TypeTainted, "node-1", ts, NodePressureData{Node: "node-1", Resource: "cpu", TaintedNodes: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sink.Run(ctx)
+
+	if rec.requests() != 1 {
+		t.Fatalf("got %d requests, want 1", rec.requests())
+	}
+	h := rec.headers[0]
+	for header, want := range map[string]string{
+		"Content-Type":   "application/json",
+		"Ce-Specversion": "1.0",
+		"Ce-Source":      "//prod-cluster",
+		"Ce-Type":        TypeTainted,
+		"Ce-Subject":     "node-1",
+		"Ce-Time":        "2026-03-01T12:00:00Z",
+	} {
+		if got := h.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+	if h.Get("Ce-Id") == "" {
+		t.Error("ce-id not set")
+	}
+	var data NodePressureData
+	if err := json.Unmarshal([]byte(rec.bodies[0]), &data); err != nil || data.Node != "node-1" || data.Resource != "cpu" {
+		t.Errorf("body = %s, err = %v", rec.bodies[0], err)
+	}
+}
+
+func TestCloudEvents_StructuredMode(t *testing.T) {
+	rec := &recorder{statuses: []int{502}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	sink := NewCloudEvents(CloudEventsConfig{
+		URL: server.URL, Mode: ModeStructured, Source: "kube-dethrottler",
+		Timeout: time.Second, MaxRetries: 1, RetryBackoff: time.Millisecond,
+	}, testLogger())
+	e := CloudEvent{
+		SpecVersion: "1.0", ID: "abc", Source: "kube-dethrottler", Type: TypePollFailed,
+		Time: time.Now(), DataContentType: "application/json",
+		Data: PollFailedData{Stage: "list", Error: "connection refused"},
+	}
+	if err := sink.Deliver(context.Background(), e, 1); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	if rec.requests() != 2 {
+		t.Errorf("got %d requests, want a retry after 502", rec.requests())
+	}
+	if ct := rec.headers[1].Get("Content-Type"); ct != "application/cloudevents+json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var got struct {
+		SpecVersion string         `json:"specversion"`
+		Type        string         `json:"type"`
+		ID          string         `json:"id"`
+		Data        PollFailedData `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(rec.bodies[1]), &got); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if got.SpecVersion != "1.0" || got.Type != TypePollFailed || got.ID != "abc" || got.Data.Stage != "list" {
+		t.Errorf("event = %+v", got)
+	}
+}
@@ -0,0 +1,329 @@
+// Package notify delivers node pressure transitions to external systems.
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/logging"
+	"github.com/Fedosin/kube-dethrottler/internal/metrics"
+)
+
+// Events reported for node transitions.
+const (
+	EventPressure  = "pressure"
+	EventRecovered = "recovered"
+)
+
+// queueSize bounds the number of events waiting for delivery; further
+// events are dropped.
+const queueSize = 1000
+
+// Breach is a single threshold exceeded on a node.
+type Breach struct {
+	Name      string  `json:"name"`
+	Resource  string  `json:"resource,omitempty"`
+	Tier      string  `json:"tier,omitempty"`
+	Window    string  `json:"window,omitempty"`
+	Observed  float64 `json:"observed"`
+	Threshold float64 `json:"threshold"`
+}
+
+// Event is a single node transition. Resource, Tier, Window, Observed and
+// Threshold describe the breach that exceeds its threshold the most and
+// are empty on recovery. TaintedNodes is the number of nodes under
+// pressure after the transition.
+type Event struct {
+	Time         time.Time `json:"time"`
+	Event        string    `json:"event"`
+	Node         string    `json:"node"`
+	Resource     string    `json:"resource,omitempty"`
+	Tier         string    `json:"tier,omitempty"`
+	Window       string    `json:"window,omitempty"`
+	Reason       string    `json:"reason"`
+	Breaches     []Breach  `json:"breaches,omitempty"`
+	Observed     float64   `json:"observed,omitempty"`
+	Threshold    float64   `json:"threshold,omitempty"`
+	TaintedNodes int       `json:"taintedNodes"`
+}
+
+// Batch is the data a webhook body is rendered from: the events queued
+// within one batch window, oldest first, and the number of nodes under
+// pressure after the last of them.
+type Batch struct {
+	Events       []Event `json:"events"`
+	TaintedNodes int     `json:"taintedNodes"`
+}
+
+// WebhookConfig configures a Webhook.
+type WebhookConfig struct {
+	URL string
+	// HeadersFile holds extra request headers, one "Name: value" per
+	// line, typically mounted from a Secret. It is read before every
+	// delivery so that rotated credentials are picked up.
+	HeadersFile string
+	// BodyTemplate is a text/template rendered with the Batch; the batch
+	// is sent as JSON when empty.
+	BodyTemplate string
+	Timeout      time.Duration
+	// MaxRetries is the number of retries after a failed delivery; the
+	// delay starts at RetryBackoff and doubles after each retry.
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// BatchWindow is how long events are collected after the first one
+	// before they are sent together, up to MaxBatchSize events. 0 sends
+	// every event on its own.
+	BatchWindow  time.Duration
+	MaxBatchSize int
+}
+
+// Webhook posts batches of events to an HTTP endpoint from a background
+// goroutine started with Run, so that a slow or failing endpoint never
+// delays the controller.
+type Webhook struct {
+	client *http.Client
+	body   *template.Template
+	events chan Event
+	logger *slog.Logger
+	cfg    WebhookConfig
+}
+
+// NewWebhook creates a Webhook. It fails if the body template does not
+// parse.
+func NewWebhook(cfg WebhookConfig, logger *slog.Logger) (*Webhook, error) {
+	w := &Webhook{
+		client: &http.Client{Timeout: cfg.Timeout},
+		events: make(chan Event, queueSize),
+		logger: logger,
+		cfg:    cfg,
+	}
+	if cfg.MaxBatchSize <= 0 {
+		w.cfg.MaxBatchSize = 1
+	}
+	if cfg.BodyTemplate != "" {
+		body, err := ParseTemplate(cfg.BodyTemplate)
+		if err != nil {
+			return nil, err
+		}
+		w.body = body
+	}
+	return w, nil
+}
+
+// ParseTemplate parses a webhook body template. Besides the standard
+// functions, templates can use json to encode any value. The config
+// package validates templates against the same function names.
+func ParseTemplate(text string) (*template.Template, error) {
+	t, err := template.New("body").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook body template: %w", err)
+	}
+	return t, nil
+}
+
+// Notify queues an event for delivery. It never blocks; events are
+// dropped when the queue is full.
+func (w *Webhook) Notify(e Event) {
+	select {
+	case w.events <- e:
+	default:
+		metrics.Notifications.WithLabelValues("webhook", "dropped").Inc()
+		w.logger.Warn("Webhook queue full, dropping event", slog.String("event", e.Event), logging.Node(e.Node))
+	}
+}
+
+// Run delivers queued events until ctx is cancelled, then makes a single
+// delivery attempt for the events still queued.
+func (w *Webhook) Run(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			w.flush()
+			return
+		}
+		select {
+		case <-ctx.Done():
+		case e := <-w.events:
+			w.send(ctx, w.collect(ctx, e), w.cfg.MaxRetries)
+		}
+	}
+}
+
+// collect gathers the events queued within the batch window that starts
+// with first.
+func (w *Webhook) collect(ctx context.Context, first Event) []Event {
+	batch := []Event{first}
+	if w.cfg.BatchWindow <= 0 {
+		return batch
+	}
+	timer := time.NewTimer(w.cfg.BatchWindow)
+	defer timer.Stop()
+	for len(batch) < w.cfg.MaxBatchSize {
+		select {
+		case e := <-w.events:
+			batch = append(batch, e)
+		case <-timer.C:
+			return batch
+		case <-ctx.Done():
+			return batch
+		}
+	}
+	return batch
+}
+
+func (w *Webhook) flush() {
+	var batch []Event
+	for {
+		select {
+		case e := <-w.events:
+			batch = append(batch, e)
+			if len(batch) < w.cfg.MaxBatchSize {
+				continue
+			}
+		default:
+		}
+		if len(batch) == 0 {
+			return
+		}
+		w.send(context.Background(), batch, 0)
+		batch = nil
+	}
+}
+
+func (w *Webhook) send(ctx context.Context, events []Event, retries int) {
+	err := w.Deliver(ctx, Batch{Events: events, TaintedNodes: events[len(events)-1].TaintedNodes}, retries)
+	if err != nil {
+		metrics.Notifications.WithLabelValues("webhook", "failed").Add(float64(len(events)))
+		w.logger.Error("Failed to deliver webhook events", slog.Int("events", len(events)), logging.Err(err))
+		return
+	}
+	metrics.Notifications.WithLabelValues("webhook", "sent").Add(float64(len(events)))
+}
+
+// errPermanent marks delivery failures that retrying cannot fix.
+var errPermanent = errors.New("permanent failure")
+
+// Deliver renders the batch and posts it, retrying up to retries times
+// with exponential backoff on network errors, 429 and 5xx responses.
+func (w *Webhook) Deliver(ctx context.Context, batch Batch, retries int) error {
+	body, err := w.render(batch)
+	if err != nil {
+		return err
+	}
+	return Retry(ctx, retries, w.cfg.RetryBackoff, func() error {
+		return w.post(ctx, body)
+	})
+}
+
+// Retry calls fn until it succeeds, fails permanently or has been retried
+// retries times, sleeping backoff before the first retry and doubling it
+// after each one. It stops retrying once ctx is cancelled.
+func Retry(ctx context.Context, retries int, backoff time.Duration, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || errors.Is(err, errPermanent) || attempt >= retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (w *Webhook) render(batch Batch) ([]byte, error) {
+	if w.body == nil {
+		body, err := json.Marshal(batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode webhook body: %w", err)
+		}
+		return body, nil
+	}
+	var buf bytes.Buffer
+	if err := w.body.Execute(&buf, batch); err != nil {
+		return nil, fmt.Errorf("failed to render webhook body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *Webhook) post(ctx context.Context, body []byte) error {
+	headers, err := ReadHeaders(w.cfg.HeadersFile)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPermanent, err)
+	}
+	// Requests in flight are completed on shutdown; the client timeout
+	// bounds them.
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: failed to build webhook request: %w", errPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return Do(w.client, req)
+}
+
+// Do sends the request and classifies the response: 2xx succeeds, network
+// errors, 429 and 5xx can be retried and anything else fails permanently.
+func Do(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: endpoint returned status %d", errPermanent, resp.StatusCode)
+	}
+}
+
+// ReadHeaders parses a headers file with one "Name: value" per line.
+// Blank lines and lines starting with # are ignored; an empty path yields
+// no headers.
+func ReadHeaders(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read headers file: %w", err)
+	}
+	headers := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(text, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("headers file %s line %d: expected \"Name: value\"", path, line)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers, scanner.Err()
+}
@@ -0,0 +1,221 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is an httptest handler that records request bodies and
+// answers with the queued status codes, then 200.
+type recorder struct {
+	statuses []int
+	bodies   []string
+	headers  []http.Header
+	mu       sync.Mutex
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, string(body))
+	r.headers = append(r.headers, req.Header.Clone())
+	if len(r.statuses) > 0 {
+		w.WriteHeader(r.statuses[0])
+		r.statuses = r.statuses[1:]
+	}
+}
+
+func (r *recorder) requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestWebhook_Deliver(t *testing.T) {
+	rec := &recorder{}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	w, err := NewWebhook(WebhookConfig{URL: server.URL, Timeout: time.Second}, testLogger())
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	batch := Batch{Events: []Event{{Event: EventPressure, Node: "node-1", Resource: "cpu", Observed: 50, Threshold: 25}}, TaintedNodes: 1}
+	if err := w.Deliver(context.Background(), batch, 0); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	var got Batch
+	if err := json.Unmarshal([]byte(rec.bodies[0]), &got); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if len(got.Events) != 1 || got.Events[0].Node != "node-1" || got.Events[0].Observed != 50 || got.TaintedNodes != 1 {
+		t.Errorf("body = %s", rec.bodies[0])
+	}
+	if ct := rec.headers[0].Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestWebhook_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		retries      int
+		wantErr      bool
+		wantRequests int
+	}{
+		{"recovers after server errors", []int{503, 500}, 3, false, 3},
+		{"gives up after retries", []int{503, 503, 503}, 2, true, 3},
+		{"retries rate limiting", []int{429}, 1, false, 2},
+		{"does not retry client errors", []int{400}, 3, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{statuses: tt.statuses}
+			server := httptest.NewServer(rec)
+			defer server.Close()
+
+			w, err := NewWebhook(WebhookConfig{URL: server.URL, Timeout: time.Second, RetryBackoff: time.Millisecond}, testLogger())
+			if err != nil {
+				t.Fatalf("NewWebhook() error = %v", err)
+			}
+			err = w.Deliver(context.Background(), Batch{Events: []Event{{Node: "node-1"}}}, tt.retries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Deliver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if rec.requests() != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", rec.requests(), tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestWebhook_HeadersAndTemplate(t *testing.T) {
+	rec := &recorder{}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	headersFile := filepath.Join(t.TempDir(), "headers")
+	content := "# mounted from a Secret\nAuthorization: Bearer s3cret\n\nContent-Type: text/plain\n"
+	if err := os.WriteFile(headersFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewWebhook(WebhookConfig{
+		URL:          server.URL,
+		HeadersFile:  headersFile,
+		BodyTemplate: `{{ len .Events }} node(s) changed, {{ .TaintedNodes }} tainted:{{ range .Events }} {{ .Node }}={{ .Event }}{{ end }}`,
+		Timeout:      time.Second,
+	}, testLogger())
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	batch := Batch{Events: []Event{{Event: EventPressure, Node: "a"}, {Event: EventRecovered, Node: "b"}}, TaintedNodes: 1}
+	if err := w.Deliver(context.Background(), batch, 0); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	if want := "2 node(s) changed, 1 tainted: a=pressure b=recovered"; rec.bodies[0] != want {
+		t.Errorf("body = %q, want %q", rec.bodies[0], want)
+	}
+	if got := rec.headers[0].Get("Authorization"); got != "Bearer s3cret" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := rec.headers[0].Get("Content-Type"); got != "text/plain" {
+		t.Errorf("Content-Type = %q, want the headers file to override it", got)
+	}
+}
+
+func TestWebhook_Batching(t *testing.T) {
+	rec := &recorder{}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	w, err := NewWebhook(WebhookConfig{
+		URL:          server.URL,
+		Timeout:      time.Second,
+		BatchWindow:  200 * time.Millisecond,
+		MaxBatchSize: 3,
+	}, testLogger())
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	for i := range 5 {
+		w.Notify(Event{Event: EventPressure, Node: "node-" + string(rune('a'+i)), TaintedNodes: i + 1})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for rec.requests() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if rec.requests() != 2 {
+		t.Fatalf("got %d requests, want 2 batches", rec.requests())
+	}
+	var first, second Batch
+	_ = json.Unmarshal([]byte(rec.bodies[0]), &first)
+	_ = json.Unmarshal([]byte(rec.bodies[1]), &second)
+	if len(first.Events) != 3 || len(second.Events) != 2 {
+		t.Errorf("batch sizes = %d, %d, want 3, 2", len(first.Events), len(second.Events))
+	}
+	if second.TaintedNodes != 5 {
+		t.Errorf("taintedNodes = %d, want the count after the last event", second.TaintedNodes)
+	}
+}
+
+func TestWebhook_FlushOnShutdown(t *testing.T) {
+	rec := &recorder{}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	w, err := NewWebhook(WebhookConfig{URL: server.URL, Timeout: time.Second}, testLogger())
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.Notify(Event{Event: EventRecovered, Node: "node-1"})
+	w.Run(ctx)
+
+	if rec.requests() != 1 || !strings.Contains(rec.bodies[0], `"node-1"`) {
+		t.Errorf("queued event not flushed on shutdown, bodies = %v", rec.bodies)
+	}
+}
+
+func TestReadHeaders_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "headers")
+	if err := os.WriteFile(path, []byte("Authorization Bearer x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadHeaders(path); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("ReadHeaders() error = %v, want a line-numbered error", err)
+	}
+	if _, err := ReadHeaders(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ReadHeaders() on a missing file should fail")
+	}
+}
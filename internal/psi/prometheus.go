@@ -0,0 +1,162 @@
+package psi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prometheusSeries maps node_exporter pressure counters to the PSI
+// resource and category they describe.
+var prometheusSeries = map[string]struct {
+	resource string
+	full     bool
+}{
+	"node_pressure_cpu_waiting_seconds_total":    {resource: ResourceCPU},
+	"node_pressure_cpu_stalled_seconds_total":    {resource: ResourceCPU, full: true},
+	"node_pressure_memory_waiting_seconds_total": {resource: ResourceMemory},
+	"node_pressure_memory_stalled_seconds_total": {resource: ResourceMemory, full: true},
+	"node_pressure_io_waiting_seconds_total":     {resource: ResourceIO},
+	"node_pressure_io_stalled_seconds_total":     {resource: ResourceIO, full: true},
+}
+
+// prometheusResponse is the subset of the Prometheus HTTP API query
+// response needed to read a range-vector selector result.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]any          `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// PrometheusSource derives PSI averages from node_exporter pressure
+// counters stored in Prometheus. The kernel's exponential averages are not
+// exported, so avg10 and avg60 are approximated by the counter rate over
+// the last minute and avg300 by the rate over the last five minutes.
+type PrometheusSource struct {
+	client    *http.Client
+	baseURL   string
+	nodeLabel string
+}
+
+// NewPrometheusSource creates a source querying the Prometheus server at
+// baseURL. nodeLabel is the series label that holds the node name.
+func NewPrometheusSource(baseURL, nodeLabel string, timeout time.Duration) *PrometheusSource {
+	return &PrometheusSource{
+		client:    &http.Client{Timeout: timeout},
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		nodeLabel: nodeLabel,
+	}
+}
+
+// Name returns SourcePrometheus.
+func (p *PrometheusSource) Name() string {
+	return SourcePrometheus
+}
+
+// FetchNodePSI queries Prometheus for the node's pressure counters.
+func (p *PrometheusSource) FetchNodePSI(ctx context.Context, nodeName string) (*NodePSI, error) {
+	short, ts, err := p.rates(ctx, nodeName, "1m")
+	if err != nil {
+		return nil, err
+	}
+	long, _, err := p.rates(ctx, nodeName, "5m")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NodePSI{Timestamp: ts, Source: SourcePrometheus}
+	for _, r := range []string{ResourceCPU, ResourceMemory, ResourceIO} {
+		pressure := result.ForResource(r)
+		someKey, fullKey := r+".some", r+".full"
+		if _, ok := short[someKey]; !ok {
+			result.Missing = append(result.Missing, r)
+			continue
+		}
+		pressure.Some = Averages{Avg10: short[someKey], Avg60: short[someKey], Avg300: long[someKey]}
+		pressure.Full = Averages{Avg10: short[fullKey], Avg60: short[fullKey], Avg300: long[fullKey]}
+	}
+	return result, nil
+}
+
+// rates returns the percentage of time stalled over the given range for
+// each "<resource>.<some|full>" series, along with the latest sample time.
+func (p *PrometheusSource) rates(ctx context.Context, nodeName, window string) (map[string]float64, time.Time, error) {
+	query := fmt.Sprintf(`{__name__=~"node_pressure_.+_seconds_total",%s=%q}[%s]`, p.nodeLabel, nodeName, window)
+	reqURL := p.baseURL + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to build prometheus query for node %s: %w", nodeName, err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to query prometheus for node %s: %w", nodeName, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read prometheus response for node %s: %w", nodeName, err)
+	}
+	var parsed prometheusResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse prometheus response for node %s: %w", nodeName, err)
+	}
+	if resp.StatusCode != http.StatusOK || parsed.Status != "success" {
+		return nil, time.Time{}, fmt.Errorf("prometheus query for node %s failed with status %d: %s", nodeName, resp.StatusCode, parsed.Error)
+	}
+	if len(parsed.Data.Result) == 0 {
+		return nil, time.Time{}, fmt.Errorf("prometheus has no pressure series for node %s", nodeName)
+	}
+
+	rates := make(map[string]float64)
+	var latest time.Time
+	for _, series := range parsed.Data.Result {
+		info, ok := prometheusSeries[series.Metric["__name__"]]
+		if !ok || len(series.Values) < 2 {
+			continue
+		}
+		t0, v0, err0 := parseSamplePair(series.Values[0])
+		t1, v1, err1 := parseSamplePair(series.Values[len(series.Values)-1])
+		if err0 != nil || err1 != nil || !t1.After(t0) || v1 < v0 {
+			continue
+		}
+		key := info.resource + ".some"
+		if info.full {
+			key = info.resource + ".full"
+		}
+		rates[key] = (v1 - v0) / t1.Sub(t0).Seconds() * 100
+		if t1.After(latest) {
+			latest = t1
+		}
+	}
+	return rates, latest, nil
+}
+
+func parseSamplePair(pair [2]any) (time.Time, float64, error) {
+	ts, ok := pair[0].(float64)
+	if !ok {
+		return time.Time{}, 0, fmt.Errorf("unexpected sample timestamp %v", pair[0])
+	}
+	raw, ok := pair[1].(string)
+	if !ok {
+		return time.Time{}, 0, fmt.Errorf("unexpected sample value %v", pair[1])
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	sec := int64(ts)
+	return time.Unix(sec, int64((ts-float64(sec))*1e9)), v, nil
+}
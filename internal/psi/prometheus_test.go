@@ -0,0 +1,75 @@
+package psi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusSource_FetchNodePSI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+		}
+		query := r.URL.Query().Get("query")
+		if !strings.Contains(query, `node="node-1"`) {
+			t.Errorf("Query does not select the node: %s", query)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// 60 seconds apart: 15s stalled -> 25%, 6s stalled -> 10%.
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"__name__":"node_pressure_cpu_waiting_seconds_total","node":"node-1"},
+			 "values":[[1700000000,"100"],[1700000060,"115"]]},
+			{"metric":{"__name__":"node_pressure_cpu_stalled_seconds_total","node":"node-1"},
+			 "values":[[1700000000,"50"],[1700000060,"56"]]}
+		]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	src := NewPrometheusSource(server.URL+"/", "node", time.Second)
+	result, err := src.FetchNodePSI(context.Background(), "node-1")
+	if err != nil {
+		t.Fatalf("FetchNodePSI returned error: %v", err)
+	}
+
+	assertFloat(t, "CPU.Some.Avg10", 25, result.CPU.Some.Avg10)
+	assertFloat(t, "CPU.Some.Avg300", 25, result.CPU.Some.Avg300)
+	assertFloat(t, "CPU.Full.Avg60", 10, result.CPU.Full.Avg60)
+	if result.Source != SourcePrometheus {
+		t.Errorf("Source: got %q, want %q", result.Source, SourcePrometheus)
+	}
+	if !result.IsMissing(ResourceMemory) || !result.IsMissing(ResourceIO) {
+		t.Errorf("Expected memory and io to be reported missing, got %v", result.Missing)
+	}
+	if want := time.Unix(1700000060, 0); !result.Timestamp.Equal(want) {
+		t.Errorf("Timestamp: got %v, want %v", result.Timestamp, want)
+	}
+}
+
+func TestPrometheusSource_NoSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	src := NewPrometheusSource(server.URL, "node", time.Second)
+	if _, err := src.FetchNodePSI(context.Background(), "node-1"); err == nil {
+		t.Fatal("Expected error when Prometheus has no series, got nil")
+	}
+}
+
+func TestPrometheusSource_QueryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	src := NewPrometheusSource(server.URL, "node", time.Second)
+	if _, err := src.FetchNodePSI(context.Background(), "node-1"); err == nil {
+		t.Fatal("Expected error from failed query, got nil")
+	}
+}
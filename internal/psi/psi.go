@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 )
@@ -22,11 +25,128 @@ type Pressure struct {
 	Full Averages `json:"full"`
 }
 
+// Value returns the average for the given category ("some" or "full") and
+// window ("avg10", "avg60" or "avg300").
+func (p *Pressure) Value(kind, window string) (float64, bool) {
+	var a Averages
+	switch kind {
+	case "some":
+		a = p.Some
+	case "full":
+		a = p.Full
+	default:
+		return 0, false
+	}
+	switch window {
+	case "avg10":
+		return a.Avg10, true
+	case "avg60":
+		return a.Avg60, true
+	case "avg300":
+		return a.Avg300, true
+	}
+	return 0, false
+}
+
+// ContainerPSI holds PSI data for a single container.
+type ContainerPSI struct {
+	Name   string   `json:"name"`
+	CPU    Pressure `json:"cpu"`
+	Memory Pressure `json:"memory"`
+	IO     Pressure `json:"io"`
+}
+
+// PodPSI holds PSI data for a pod and its containers.
+type PodPSI struct {
+	Namespace  string         `json:"namespace"`
+	Name       string         `json:"name"`
+	CPU        Pressure       `json:"cpu"`
+	Memory     Pressure       `json:"memory"`
+	IO         Pressure       `json:"io"`
+	Containers []ContainerPSI `json:"containers,omitempty"`
+}
+
+// ForResource returns the pod's pressure data for the given resource, or
+// nil if the resource name is not recognized.
+func (p *PodPSI) ForResource(resource string) *Pressure {
+	switch resource {
+	case ResourceCPU:
+		return &p.CPU
+	case ResourceMemory:
+		return &p.Memory
+	case ResourceIO:
+		return &p.IO
+	}
+	return nil
+}
+
+// Resource names as they appear in NodePSI.Missing.
+const (
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+	ResourceIO     = "io"
+)
+
 // NodePSI holds PSI data for all resources on a node.
 type NodePSI struct {
-	CPU    Pressure
-	Memory Pressure
-	IO     Pressure
+	// Timestamp is the time the kubelet collected the sample. It is zero
+	// when the Summary response did not carry one.
+	Timestamp time.Time `json:"timestamp"`
+	CPU       Pressure  `json:"cpu"`
+	Memory    Pressure  `json:"memory"`
+	IO        Pressure  `json:"io"`
+	// Missing lists the resources for which the kubelet reported no PSI
+	// data (cgroup v1, PSI disabled in the kernel, or an old kubelet).
+	// Their Pressure values are zero and must not be read as "no pressure".
+	Missing []string `json:"missing,omitempty"`
+	// Source names the Source that supplied the sample.
+	Source string `json:"source,omitempty"`
+	// Pods holds per-pod PSI when the source was asked to include it.
+	Pods []PodPSI `json:"pods,omitempty"`
+	// MemoryAvailableBytes, FSAvailableBytes and FSCapacityBytes are the
+	// node resource stats from the Summary response, nil when not reported.
+	MemoryAvailableBytes *uint64 `json:"memoryAvailableBytes,omitempty"`
+	FSAvailableBytes     *uint64 `json:"fsAvailableBytes,omitempty"`
+	FSCapacityBytes      *uint64 `json:"fsCapacityBytes,omitempty"`
+}
+
+// ForResource returns the pressure data for the given resource, or nil if
+// the resource name is not recognized.
+func (n *NodePSI) ForResource(resource string) *Pressure {
+	switch resource {
+	case ResourceCPU:
+		return &n.CPU
+	case ResourceMemory:
+		return &n.Memory
+	case ResourceIO:
+		return &n.IO
+	}
+	return nil
+}
+
+// Value returns the average named by a dotted metric such as
+// "cpu.some.avg10".
+func (n *NodePSI) Value(metric string) (float64, bool) {
+	parts := strings.Split(metric, ".")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	p := n.ForResource(parts[0])
+	if p == nil {
+		return 0, false
+	}
+	return p.Value(parts[1], parts[2])
+}
+
+// ValidMetric reports whether metric names a PSI average, e.g. "io.full.avg60".
+func ValidMetric(metric string) bool {
+	_, ok := (&NodePSI{}).Value(metric)
+	return ok
+}
+
+// IsMissing reports whether PSI data for the given resource was absent.
+func (n *NodePSI) IsMissing(resource string) bool {
+	return slices.Contains(n.Missing, resource)
 }
 
 // summaryResponse is the minimal structure needed to extract node-level PSI
@@ -34,21 +154,73 @@ type NodePSI struct {
 type summaryResponse struct {
 	Node struct {
 		CPU struct {
-			PSI *Pressure `json:"psi"`
+			Time time.Time `json:"time"`
+			PSI  *Pressure `json:"psi"`
 		} `json:"cpu"`
 		Memory struct {
-			PSI *Pressure `json:"psi"`
+			Time           time.Time `json:"time"`
+			PSI            *Pressure `json:"psi"`
+			AvailableBytes *uint64   `json:"availableBytes"`
 		} `json:"memory"`
 		IO struct {
 			PSI *Pressure `json:"psi"`
 		} `json:"io"`
+		FS struct {
+			AvailableBytes *uint64 `json:"availableBytes"`
+			CapacityBytes  *uint64 `json:"capacityBytes"`
+		} `json:"fs"`
 	} `json:"node"`
 }
 
+// resourcePSI is the PSI section shared by the node, pod and container
+// entries of the Summary response.
+type resourcePSI struct {
+	CPU struct {
+		PSI *Pressure `json:"psi"`
+	} `json:"cpu"`
+	Memory struct {
+		PSI *Pressure `json:"psi"`
+	} `json:"memory"`
+	IO struct {
+		PSI *Pressure `json:"psi"`
+	} `json:"io"`
+}
+
+// summaryPods is the structure needed to extract pod and container PSI
+// from the kubelet Summary API response.
+type summaryPods struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		resourcePSI
+		Containers []struct {
+			Name string `json:"name"`
+			resourcePSI
+		} `json:"containers"`
+	} `json:"pods"`
+}
+
+func (r *resourcePSI) pressures() (cpu, memory, io Pressure, ok bool) {
+	if r.CPU.PSI != nil {
+		cpu, ok = *r.CPU.PSI, true
+	}
+	if r.Memory.PSI != nil {
+		memory, ok = *r.Memory.PSI, true
+	}
+	if r.IO.PSI != nil {
+		io, ok = *r.IO.PSI, true
+	}
+	return cpu, memory, io, ok
+}
+
 // Fetcher retrieves PSI metrics from the kubelet Summary API via the
 // kube-apiserver proxy.
 type Fetcher struct {
 	clientset kubernetes.Interface
+	// IncludePods enables decoding of pod and container PSI into NodePSI.Pods.
+	IncludePods bool
 }
 
 // NewFetcher creates a new PSI metrics fetcher.
@@ -56,6 +228,11 @@ func NewFetcher(clientset kubernetes.Interface) *Fetcher {
 	return &Fetcher{clientset: clientset}
 }
 
+// Name returns SourceSummary.
+func (f *Fetcher) Name() string {
+	return SourceSummary
+}
+
 // FetchNodePSI retrieves PSI metrics for a given node by calling
 // /api/v1/nodes/<nodeName>/proxy/stats/summary through the kube-apiserver.
 func (f *Fetcher) FetchNodePSI(ctx context.Context, nodeName string) (*NodePSI, error) {
@@ -76,16 +253,77 @@ func (f *Fetcher) FetchNodePSI(ctx context.Context, nodeName string) (*NodePSI,
 		return nil, fmt.Errorf("failed to parse summary stats for node %s: %w", nodeName, err)
 	}
 
-	result := &NodePSI{}
+	result := &NodePSI{
+		Timestamp:            summary.Node.CPU.Time,
+		Source:               SourceSummary,
+		MemoryAvailableBytes: summary.Node.Memory.AvailableBytes,
+		FSAvailableBytes:     summary.Node.FS.AvailableBytes,
+		FSCapacityBytes:      summary.Node.FS.CapacityBytes,
+	}
+	if result.Timestamp.IsZero() {
+		result.Timestamp = summary.Node.Memory.Time
+	}
 	if summary.Node.CPU.PSI != nil {
 		result.CPU = *summary.Node.CPU.PSI
+	} else {
+		result.Missing = append(result.Missing, ResourceCPU)
 	}
 	if summary.Node.Memory.PSI != nil {
 		result.Memory = *summary.Node.Memory.PSI
+	} else {
+		result.Missing = append(result.Missing, ResourceMemory)
 	}
 	if summary.Node.IO.PSI != nil {
 		result.IO = *summary.Node.IO.PSI
+	} else {
+		result.Missing = append(result.Missing, ResourceIO)
+	}
+
+	if f.IncludePods {
+		pods, err := decodePods(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pod stats for node %s: %w", nodeName, err)
+		}
+		result.Pods = pods
 	}
 
 	return result, nil
 }
+
+// decodePods extracts pod and container PSI, skipping pods without any.
+func decodePods(data []byte) ([]PodPSI, error) {
+	var summary summaryPods
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, err
+	}
+
+	pods := make([]PodPSI, 0, len(summary.Pods))
+	for i := range summary.Pods {
+		sp := &summary.Pods[i]
+		cpu, memory, io, ok := sp.pressures()
+		if !ok {
+			continue
+		}
+		pod := PodPSI{
+			Namespace: sp.PodRef.Namespace,
+			Name:      sp.PodRef.Name,
+			CPU:       cpu,
+			Memory:    memory,
+			IO:        io,
+		}
+		for j := range sp.Containers {
+			cCPU, cMemory, cIO, ok := sp.Containers[j].pressures()
+			if !ok {
+				continue
+			}
+			pod.Containers = append(pod.Containers, ContainerPSI{
+				Name:   sp.Containers[j].Name,
+				CPU:    cCPU,
+				Memory: cMemory,
+				IO:     cIO,
+			})
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -33,11 +34,13 @@ func TestFetchNodePSI_AllMetrics(t *testing.T) {
 				},
 			},
 			"memory": map[string]any{
+				"availableBytes": 1073741824,
 				"psi": map[string]any{
 					"some": map[string]any{"avg10": 22.0, "avg60": 15.0, "avg300": 10.0, "total": 200000},
 					"full": map[string]any{"avg10": 10.0, "avg60": 7.5, "avg300": 4.0, "total": 120000},
 				},
 			},
+			"fs": map[string]any{"availableBytes": 2000, "capacityBytes": 10000},
 			"io": map[string]any{
 				"psi": map[string]any{
 					"some": map[string]any{"avg10": 30.0, "avg60": 20.0, "avg300": 12.0, "total": 300000},
@@ -76,6 +79,13 @@ func TestFetchNodePSI_AllMetrics(t *testing.T) {
 
 	assertFloat(t, "IO.Some.Avg10", 30.0, result.IO.Some.Avg10)
 	assertFloat(t, "IO.Full.Avg10", 15.0, result.IO.Full.Avg10)
+
+	if result.MemoryAvailableBytes == nil || *result.MemoryAvailableBytes != 1073741824 {
+		t.Errorf("MemoryAvailableBytes: got %v, want 1073741824", result.MemoryAvailableBytes)
+	}
+	if result.FSAvailableBytes == nil || *result.FSAvailableBytes != 2000 || result.FSCapacityBytes == nil || *result.FSCapacityBytes != 10000 {
+		t.Errorf("FS stats: got available=%v capacity=%v", result.FSAvailableBytes, result.FSCapacityBytes)
+	}
 }
 
 func TestFetchNodePSI_NilPSIFields(t *testing.T) {
@@ -103,6 +113,50 @@ func TestFetchNodePSI_NilPSIFields(t *testing.T) {
 	assertFloat(t, "CPU.Some.Avg10", 0, result.CPU.Some.Avg10)
 	assertFloat(t, "Memory.Some.Avg10", 0, result.Memory.Some.Avg10)
 	assertFloat(t, "IO.Some.Avg10", 0, result.IO.Some.Avg10)
+
+	for _, r := range []string{ResourceCPU, ResourceMemory, ResourceIO} {
+		if !result.IsMissing(r) {
+			t.Errorf("Expected %s to be reported missing, got %v", r, result.Missing)
+		}
+	}
+}
+
+func TestFetchNodePSI_Timestamp(t *testing.T) {
+	response := map[string]any{
+		"node": map[string]any{
+			"cpu": map[string]any{
+				"time": "2026-01-02T03:04:05Z",
+				"psi": map[string]any{
+					"some": map[string]any{"avg10": 1.0},
+					"full": map[string]any{"avg10": 0.0},
+				},
+			},
+		},
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	clientset := newTestClientset(t, handler)
+	fetcher := NewFetcher(clientset)
+
+	result, err := fetcher.FetchNodePSI(context.Background(), "test-node")
+	if err != nil {
+		t.Fatalf("FetchNodePSI returned error: %v", err)
+	}
+
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if !result.Timestamp.Equal(want) {
+		t.Errorf("Timestamp: got %v, want %v", result.Timestamp, want)
+	}
+	if result.IsMissing(ResourceCPU) {
+		t.Error("Expected cpu not to be reported missing")
+	}
+	if !result.IsMissing(ResourceMemory) || !result.IsMissing(ResourceIO) {
+		t.Errorf("Expected memory and io to be reported missing, got %v", result.Missing)
+	}
 }
 
 func TestFetchNodePSI_PartialPSI(t *testing.T) {
@@ -207,3 +261,88 @@ func assertFloat(t *testing.T, field string, want, got float64) {
 		t.Errorf("%s: got %v, want %v", field, got, want)
 	}
 }
+
+func TestFetchNodePSI_IncludePods(t *testing.T) {
+	cpu := map[string]any{
+		"psi": map[string]any{
+			"some": map[string]any{"avg10": 40.0},
+			"full": map[string]any{"avg10": 10.0},
+		},
+	}
+	response := map[string]any{
+		"node": map[string]any{"cpu": cpu},
+		"pods": []any{
+			map[string]any{
+				"podRef": map[string]any{"name": "noisy", "namespace": "batch"},
+				"cpu":    cpu,
+				"containers": []any{
+					map[string]any{"name": "worker", "cpu": cpu},
+					map[string]any{"name": "sidecar"},
+				},
+			},
+			map[string]any{
+				"podRef": map[string]any{"name": "no-psi", "namespace": "default"},
+			},
+		},
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	clientset := newTestClientset(t, handler)
+
+	fetcher := NewFetcher(clientset)
+	result, err := fetcher.FetchNodePSI(context.Background(), "test-node")
+	if err != nil {
+		t.Fatalf("FetchNodePSI returned error: %v", err)
+	}
+	if len(result.Pods) != 0 {
+		t.Errorf("Expected pods not to be decoded by default, got %d", len(result.Pods))
+	}
+
+	fetcher.IncludePods = true
+	result, err = fetcher.FetchNodePSI(context.Background(), "test-node")
+	if err != nil {
+		t.Fatalf("FetchNodePSI returned error: %v", err)
+	}
+	if len(result.Pods) != 1 {
+		t.Fatalf("Expected 1 pod with PSI, got %d", len(result.Pods))
+	}
+	pod := result.Pods[0]
+	if pod.Namespace != "batch" || pod.Name != "noisy" {
+		t.Errorf("Unexpected pod: %s/%s", pod.Namespace, pod.Name)
+	}
+	assertFloat(t, "Pod.CPU.Some.Avg10", 40.0, pod.CPU.Some.Avg10)
+	if len(pod.Containers) != 1 || pod.Containers[0].Name != "worker" {
+		t.Errorf("Expected only the worker container, got %+v", pod.Containers)
+	}
+}
+
+func TestNodePSI_Value(t *testing.T) {
+	n := &NodePSI{
+		CPU: Pressure{Some: Averages{Avg10: 12.5}},
+		IO:  Pressure{Full: Averages{Avg300: 3}},
+	}
+
+	tests := []struct {
+		metric string
+		want   float64
+		ok     bool
+	}{
+		{metric: "cpu.some.avg10", want: 12.5, ok: true},
+		{metric: "io.full.avg300", want: 3, ok: true},
+		{metric: "memory.some.avg60", want: 0, ok: true},
+		{metric: "gpu.some.avg10", ok: false},
+		{metric: "cpu.partial.avg10", ok: false},
+		{metric: "cpu.some", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := n.Value(tt.metric)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Value(%q) = %v, %v, want %v, %v", tt.metric, got, ok, tt.want, tt.ok)
+		}
+	}
+}
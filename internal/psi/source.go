@@ -0,0 +1,120 @@
+package psi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the built-in PSI sources, as recorded in NodePSI.Source.
+const (
+	SourceSummary    = "summary"
+	SourcePrometheus = "prometheus"
+	SourceLastKnown  = "lastKnown"
+)
+
+// Source supplies PSI samples for nodes.
+type Source interface {
+	// Name identifies the source in logs, metrics and NodePSI.Source.
+	Name() string
+	FetchNodePSI(ctx context.Context, nodeName string) (*NodePSI, error)
+}
+
+var (
+	_ Source = (*Fetcher)(nil)
+	_ Source = (*PrometheusSource)(nil)
+	_ Source = (*CompositeSource)(nil)
+)
+
+type lastKnownSample struct {
+	sample    *NodePSI
+	fetchedAt time.Time
+}
+
+// CompositeSource tries a list of sources in order and returns the first
+// successful sample. When every source fails it can fall back to the last
+// successful sample for the node, provided it is younger than a maximum age.
+type CompositeSource struct {
+	lastKnown       map[string]lastKnownSample
+	now             func() time.Time
+	sources         []Source
+	lastKnownMaxAge time.Duration
+	mu              sync.Mutex
+}
+
+// NewCompositeSource creates a CompositeSource over the given sources.
+// A lastKnownMaxAge of 0 disables the last-known fallback.
+func NewCompositeSource(sources []Source, lastKnownMaxAge time.Duration) *CompositeSource {
+	return &CompositeSource{
+		sources:         sources,
+		lastKnownMaxAge: lastKnownMaxAge,
+		lastKnown:       make(map[string]lastKnownSample),
+		now:             time.Now,
+	}
+}
+
+// Name returns the names of the underlying sources joined in order.
+func (s *CompositeSource) Name() string {
+	names := make([]string, 0, len(s.sources)+1)
+	for _, src := range s.sources {
+		names = append(names, src.Name())
+	}
+	if s.lastKnownMaxAge > 0 {
+		names = append(names, SourceLastKnown)
+	}
+	return strings.Join(names, ",")
+}
+
+// FetchNodePSI returns the first successful sample from the configured
+// sources, or a recent last-known sample if all of them fail.
+func (s *CompositeSource) FetchNodePSI(ctx context.Context, nodeName string) (*NodePSI, error) {
+	var errs []error
+	for _, src := range s.sources {
+		sample, err := src.FetchNodePSI(ctx, nodeName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", src.Name(), err))
+			continue
+		}
+		if sample.Source == "" {
+			sample.Source = src.Name()
+		}
+		s.remember(nodeName, sample)
+		return sample, nil
+	}
+
+	if sample, ok := s.recall(nodeName); ok {
+		return sample, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (s *CompositeSource) remember(nodeName string, sample *NodePSI) {
+	if s.lastKnownMaxAge <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastKnown[nodeName] = lastKnownSample{sample: sample, fetchedAt: s.now()}
+}
+
+func (s *CompositeSource) recall(nodeName string) (*NodePSI, bool) {
+	if s.lastKnownMaxAge <= 0 {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.lastKnown[nodeName]
+	if !ok {
+		return nil, false
+	}
+	if s.now().Sub(last.fetchedAt) > s.lastKnownMaxAge {
+		delete(s.lastKnown, nodeName)
+		return nil, false
+	}
+	sample := *last.sample
+	sample.Source = SourceLastKnown
+	return &sample, true
+}
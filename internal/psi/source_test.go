@@ -0,0 +1,94 @@
+package psi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubSource struct {
+	sample *NodePSI
+	err    error
+	name   string
+	calls  int
+}
+
+func (s *stubSource) Name() string { return s.name }
+
+func (s *stubSource) FetchNodePSI(_ context.Context, _ string) (*NodePSI, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	sample := *s.sample
+	return &sample, nil
+}
+
+func TestCompositeSource_FirstSuccessfulSourceWins(t *testing.T) {
+	primary := &stubSource{name: "primary", err: errors.New("proxy error")}
+	secondary := &stubSource{name: "secondary", sample: &NodePSI{CPU: Pressure{Some: Averages{Avg10: 42}}}}
+	tertiary := &stubSource{name: "tertiary", sample: &NodePSI{}}
+
+	src := NewCompositeSource([]Source{primary, secondary, tertiary}, 0)
+
+	result, err := src.FetchNodePSI(context.Background(), "node-1")
+	if err != nil {
+		t.Fatalf("FetchNodePSI returned error: %v", err)
+	}
+	assertFloat(t, "CPU.Some.Avg10", 42, result.CPU.Some.Avg10)
+	if result.Source != "secondary" {
+		t.Errorf("Source: got %q, want %q", result.Source, "secondary")
+	}
+	if tertiary.calls != 0 {
+		t.Errorf("Expected tertiary source not to be called, got %d calls", tertiary.calls)
+	}
+}
+
+func TestCompositeSource_AllFail(t *testing.T) {
+	src := NewCompositeSource([]Source{
+		&stubSource{name: "a", err: errors.New("first")},
+		&stubSource{name: "b", err: errors.New("second")},
+	}, 0)
+
+	_, err := src.FetchNodePSI(context.Background(), "node-1")
+	if err == nil {
+		t.Fatal("Expected error when all sources fail, got nil")
+	}
+}
+
+func TestCompositeSource_LastKnown(t *testing.T) {
+	primary := &stubSource{name: SourceSummary, sample: &NodePSI{CPU: Pressure{Some: Averages{Avg10: 30}}}}
+	src := NewCompositeSource([]Source{primary}, time.Minute)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	src.now = func() time.Time { return now }
+
+	if _, err := src.FetchNodePSI(context.Background(), "node-1"); err != nil {
+		t.Fatalf("FetchNodePSI returned error: %v", err)
+	}
+
+	primary.err = errors.New("proxy error")
+	now = now.Add(30 * time.Second)
+
+	result, err := src.FetchNodePSI(context.Background(), "node-1")
+	if err != nil {
+		t.Fatalf("Expected last-known sample, got error: %v", err)
+	}
+	if result.Source != SourceLastKnown {
+		t.Errorf("Source: got %q, want %q", result.Source, SourceLastKnown)
+	}
+	assertFloat(t, "CPU.Some.Avg10", 30, result.CPU.Some.Avg10)
+
+	now = now.Add(time.Minute)
+	if _, err := src.FetchNodePSI(context.Background(), "node-1"); err == nil {
+		t.Error("Expected error once the last-known sample expired")
+	}
+}
+
+func TestCompositeSource_Name(t *testing.T) {
+	src := NewCompositeSource([]Source{&stubSource{name: SourceSummary}, &stubSource{name: SourcePrometheus}}, time.Minute)
+	if got, want := src.Name(), "summary,prometheus,lastKnown"; got != want {
+		t.Errorf("Name: got %q, want %q", got, want)
+	}
+}
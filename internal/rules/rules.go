@@ -0,0 +1,115 @@
+package rules
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+// Rule is a named CEL expression evaluated against a node PSI sample.
+//
+// Expressions see the variables cpu, memory and io, each a Pressure, so
+// that e.g. `cpu.some.avg10 > 40 && cpu.some.avg60 > 20` is valid and a
+// misspelt field such as `cpu.sme.avg10` fails to compile. The
+// variable stats holds memoryAvailableBytes, fsAvailableBytes and
+// fsCapacityBytes when the source reported them; test for them with has().
+type Rule struct {
+	program    cel.Program
+	Name       string
+	Expression string
+}
+
+// Pressure is the type of the cpu, memory and io variables.
+type Pressure struct {
+	Some Averages `cel:"some"`
+	Full Averages `cel:"full"`
+}
+
+// Averages holds the PSI averages of a Pressure.
+type Averages struct {
+	Avg10  float64 `cel:"avg10"`
+	Avg60  float64 `cel:"avg60"`
+	Avg300 float64 `cel:"avg300"`
+}
+
+var env *cel.Env
+
+func init() {
+	pressure := cel.ObjectType("rules.Pressure")
+	var err error
+	env, err = cel.NewEnv(
+		ext.NativeTypes(reflect.TypeFor[Pressure](), ext.ParseStructTags(true)),
+		// Let integer literals be compared with the double averages.
+		cel.CrossTypeNumericComparisons(true),
+		cel.Variable(psi.ResourceCPU, pressure),
+		cel.Variable(psi.ResourceMemory, pressure),
+		cel.Variable(psi.ResourceIO, pressure),
+		cel.Variable("stats", cel.MapType(cel.StringType, cel.DoubleType)),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create CEL environment: %v", err))
+	}
+}
+
+// Compile parses and type-checks a rule expression, which must evaluate to
+// a bool.
+func Compile(name, expression string) (*Rule, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("rule %s: %w", name, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("rule %s: expression must evaluate to bool, got %s", name, ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", name, err)
+	}
+	return &Rule{Name: name, Expression: expression, program: program}, nil
+}
+
+// Eval evaluates the rule against the variables returned by Vars.
+func (r *Rule) Eval(vars map[string]any) (bool, error) {
+	out, _, err := r.program.Eval(vars)
+	if err != nil {
+		return false, fmt.Errorf("rule %s: %w", r.Name, err)
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("rule %s: expression returned %T, want bool", r.Name, out.Value())
+	}
+	return matched, nil
+}
+
+// Vars builds the rule variables from a node PSI sample.
+func Vars(n *psi.NodePSI) map[string]any {
+	stats := map[string]float64{}
+	if n.MemoryAvailableBytes != nil {
+		stats["memoryAvailableBytes"] = float64(*n.MemoryAvailableBytes)
+	}
+	if n.FSAvailableBytes != nil {
+		stats["fsAvailableBytes"] = float64(*n.FSAvailableBytes)
+	}
+	if n.FSCapacityBytes != nil {
+		stats["fsCapacityBytes"] = float64(*n.FSCapacityBytes)
+	}
+
+	return map[string]any{
+		psi.ResourceCPU:    pressureVars(n.CPU),
+		psi.ResourceMemory: pressureVars(n.Memory),
+		psi.ResourceIO:     pressureVars(n.IO),
+		"stats":            stats,
+	}
+}
+
+func pressureVars(p psi.Pressure) Pressure {
+	return Pressure{Some: averagesVars(p.Some), Full: averagesVars(p.Full)}
+}
+
+func averagesVars(a psi.Averages) Averages {
+	return Averages{Avg10: a.Avg10, Avg60: a.Avg60, Avg300: a.Avg300}
+}
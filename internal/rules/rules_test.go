@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		errMsg     string
+	}{
+		{name: "syntax error", expression: "cpu.some.avg10 >", errMsg: "Syntax error"},
+		{name: "unknown variable", expression: "gpu.some.avg10 > 1.0", errMsg: "undeclared reference"},
+		{name: "non-bool result", expression: "cpu.some.avg10 + 1.0", errMsg: "must evaluate to bool"},
+		{name: "unknown pressure", expression: "cpu.sme.avg10 > 40.0", errMsg: "undefined field 'sme'"},
+		{name: "unknown window", expression: "memory.full.avg5 > 40.0", errMsg: "undefined field 'avg5'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.name, tt.expression)
+			if err == nil {
+				t.Fatalf("Compile(%q) error = nil, want error", tt.expression)
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Compile(%q) error = %v, want error containing %q", tt.expression, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestRule_Eval(t *testing.T) {
+	available := uint64(512 << 20)
+	sample := &psi.NodePSI{
+		CPU:                  psi.Pressure{Some: psi.Averages{Avg10: 45, Avg60: 25}},
+		Memory:               psi.Pressure{Full: psi.Averages{Avg10: 6}},
+		IO:                   psi.Pressure{Some: psi.Averages{Avg10: 1}},
+		MemoryAvailableBytes: &available,
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{expression: "cpu.some.avg10 > 40.0 && cpu.some.avg60 > 20.0", want: true},
+		{expression: "cpu.some.avg10 > 40.0 && cpu.some.avg60 > 30.0", want: false},
+		{expression: "memory.full.avg10 > 5.0 && !(io.some.avg10 < 2.0)", want: false},
+		{expression: "has(stats.memoryAvailableBytes) && stats.memoryAvailableBytes < 1073741824.0", want: true},
+		{expression: "has(stats.fsAvailableBytes)", want: false},
+		{expression: "cpu.some.avg10 > 40 && memory.full.avg10 >= 6", want: true},
+		{expression: "io.some.avg10 > 1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			rule, err := Compile("test", tt.expression)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			got, err := rule.Eval(Vars(sample))
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package simulate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+var (
+	_ kubernetes.KubeClientInterface = (*cluster)(nil)
+	_ psi.Source                     = (*replaySource)(nil)
+)
+
+// cluster is an in-memory KubeClientInterface holding the nodes of a
+// trace. Nodes join when their first sample is replayed.
+type cluster struct {
+	nodes map[string]*kubernetes.NodeInfo
+	// evicted lists the evicted pods as "namespace/name".
+	evicted []string
+	mu      sync.Mutex
+}
+
+func newCluster() *cluster {
+	return &cluster{nodes: make(map[string]*kubernetes.NodeInfo)}
+}
+
+// observe adds the node of a replayed sample with its recorded labels. The
+// labels of later samples are ignored: they were recorded from the live
+// cluster and would overwrite the label set by the simulated actuator.
+func (c *cluster) observe(s Sample) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.nodes[s.Node]; ok {
+		return
+	}
+	node := &kubernetes.NodeInfo{
+		Name:        s.Node,
+		Labels:      map[string]string{},
+		Annotations: map[string]string{},
+		Conditions:  map[string]bool{},
+	}
+	maps.Copy(node.Labels, s.Labels)
+	c.nodes[s.Node] = node
+}
+
+func (c *cluster) node(name string) (*kubernetes.NodeInfo, error) {
+	node, ok := c.nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("node %s not found", name)
+	}
+	return node, nil
+}
+
+func (c *cluster) ListNodes(_ context.Context, labelSelector string) ([]kubernetes.NodeInfo, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var nodes []kubernetes.NodeInfo
+	for _, name := range slices.Sorted(maps.Keys(c.nodes)) {
+		node := c.nodes[name]
+		if !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		nodes = append(nodes, kubernetes.NodeInfo{
+			Name:        node.Name,
+			Labels:      maps.Clone(node.Labels),
+			Annotations: maps.Clone(node.Annotations),
+			Conditions:  maps.Clone(node.Conditions),
+			Taints:      slices.Clone(node.Taints),
+		})
+	}
+	return nodes, nil
+}
+
+func (c *cluster) ApplyTaint(_ context.Context, nodeName, taintKey, taintValue, taintEffect string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, err := c.node(nodeName)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(node.Taints, matchTaint(taintKey, taintEffect)) {
+		node.Taints = append(node.Taints, corev1.Taint{Key: taintKey, Value: taintValue, Effect: corev1.TaintEffect(taintEffect)})
+	}
+	return nil
+}
+
+func (c *cluster) RemoveTaint(_ context.Context, nodeName, taintKey, taintEffect string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, err := c.node(nodeName)
+	if err != nil {
+		return err
+	}
+	node.Taints = slices.DeleteFunc(node.Taints, matchTaint(taintKey, taintEffect))
+	return nil
+}
+
+func (c *cluster) HasTaint(_ context.Context, nodeName, taintKey, taintEffect string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, err := c.node(nodeName)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(node.Taints, matchTaint(taintKey, taintEffect)), nil
+}
+
+func matchTaint(key, effect string) func(corev1.Taint) bool {
+	return func(t corev1.Taint) bool {
+		return t.Key == key && string(t.Effect) == effect
+	}
+}
+
+func (c *cluster) RecordNodeEvent(_ context.Context, _, _, _, _ string) error {
+	return nil
+}
+
+func (c *cluster) SetNodeAnnotation(_ context.Context, nodeName, key, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, err := c.node(nodeName)
+	if err != nil {
+		return err
+	}
+	node.Annotations[key] = value
+	return nil
+}
+
+func (c *cluster) RemoveNodeAnnotation(_ context.Context, nodeName, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, err := c.node(nodeName)
+	if err != nil {
+		return err
+	}
+	delete(node.Annotations, key)
+	return nil
+}
+
+func (c *cluster) PatchNodeAnnotations(_ context.Context, nodeName string, annotations map[string]*string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, err := c.node(nodeName)
+	if err != nil {
+		return err
+	}
+	for key, value := range annotations {
+		if value == nil {
+			delete(node.Annotations, key)
+		} else {
+			node.Annotations[key] = *value
+		}
+	}
+	return nil
+}
+
+// GetPodLabels returns no labels: traces do not record pods' labels.
+func (c *cluster) GetPodLabels(_ context.Context, _, _ string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (c *cluster) EvictPod(_ context.Context, namespace, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evicted = append(c.evicted, namespace+"/"+name)
+	return nil
+}
+
+func (c *cluster) SetNodeCondition(_ context.Context, nodeName, conditionType string, status bool, _, _ string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, err := c.node(nodeName)
+	if err != nil {
+		return err
+	}
+	node.Conditions[conditionType] = status
+	return nil
+}
+
+func (c *cluster) SetNodeLabel(_ context.Context, nodeName, key, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, err := c.node(nodeName)
+	if err != nil {
+		return err
+	}
+	node.Labels[key] = value
+	return nil
+}
+
+// GetConfigMapValue returns "": the simulation is never paused.
+func (c *cluster) GetConfigMapValue(_ context.Context, _, _, _ string) (string, error) {
+	return "", nil
+}
+
+// replaySource serves the latest sample of each node at or before the
+// simulated time.
+type replaySource struct {
+	latest map[string]Sample
+}
+
+func (r *replaySource) Name() string {
+	return "trace"
+}
+
+func (r *replaySource) FetchNodePSI(_ context.Context, nodeName string) (*psi.NodePSI, error) {
+	s, ok := r.latest[nodeName]
+	if !ok {
+		return nil, fmt.Errorf("no sample of node %s", nodeName)
+	}
+	if s.Error != "" {
+		return nil, errors.New(s.Error)
+	}
+	sample := *s.PSI
+	return &sample, nil
+}
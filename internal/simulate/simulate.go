@@ -0,0 +1,153 @@
+package simulate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/controller"
+)
+
+// Kinds of timeline events.
+const (
+	EventTainted  = "tainted"
+	EventReleased = "released"
+)
+
+// DefaultFlapWindow is the default Options.FlapWindow.
+const DefaultFlapWindow = 10 * time.Minute
+
+// Options tune a simulation.
+type Options struct {
+	// FlapWindow is how soon after its release a node put under pressure
+	// again counts as a flap.
+	FlapWindow time.Duration
+}
+
+// Event is a change of a node's pressure state during a simulation.
+type Event struct {
+	Time time.Time `json:"time"`
+	Node string    `json:"node"`
+	Kind string    `json:"kind"`
+	// Reason lists the checks that fired when the node was tainted.
+	Reason string `json:"reason,omitempty"`
+	// Flap is set when the node was tainted within the flap window of its
+	// previous release.
+	Flap bool `json:"flap,omitempty"`
+}
+
+// Summary aggregates the events of a simulation.
+type Summary struct {
+	Start              time.Time `json:"start"`
+	End                time.Time `json:"end"`
+	Polls              int       `json:"polls"`
+	Nodes              int       `json:"nodes"`
+	Taints             int       `json:"taints"`
+	Releases           int       `json:"releases"`
+	Flaps              int       `json:"flaps"`
+	Evictions          int       `json:"evictions"`
+	MaxTaintedNodes    int       `json:"maxTaintedNodes"`
+	TaintedNodeMinutes float64   `json:"taintedNodeMinutes"`
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	Timeline []Event `json:"timeline"`
+	Summary  Summary `json:"summary"`
+}
+
+// Run replays a trace through the controller configured by cfg, polling
+// every cfg.PollInterval of simulated time from the first sample to the
+// last. Each poll sees the latest sample of every node recorded up to
+// then. Actions are applied to an in-memory cluster; webhook and
+// CloudEvents notifications and the pause switch are disabled.
+func Run(ctx context.Context, cfg *config.Config, trace []Sample, opts Options, logger *slog.Logger) (*Result, error) {
+	if len(trace) == 0 {
+		return nil, errors.New("the trace has no samples")
+	}
+	if cfg.PollInterval <= 0 {
+		return nil, errors.New("pollInterval must be positive")
+	}
+	if opts.FlapWindow == 0 {
+		opts.FlapWindow = DefaultFlapWindow
+	}
+
+	start, end := trace[0].Time, trace[len(trace)-1].Time
+	now := start
+	nodes := newCluster()
+	source := &replaySource{latest: make(map[string]Sample)}
+	ctrl := controller.NewController(offline(cfg), nodes, source, logger)
+	ctrl.SetClock(func() time.Time { return now })
+
+	result := &Result{Timeline: []Event{}, Summary: Summary{Start: start, End: end}}
+	taintedSince := make(map[string]time.Time)
+	releasedAt := make(map[string]time.Time)
+	next := 0
+	for ; !now.After(end); now = now.Add(cfg.PollInterval) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for ; next < len(trace) && !trace[next].Time.After(now); next++ {
+			nodes.observe(trace[next])
+			source.latest[trace[next].Node] = trace[next]
+		}
+
+		ctrl.Poll(ctx)
+		result.Summary.Polls++
+
+		for _, e := range ctrl.Evaluations() {
+			since, tainted := taintedSince[e.Node]
+			switch {
+			case e.Tainted && !tainted:
+				released, ok := releasedAt[e.Node]
+				event := Event{Time: now, Node: e.Node, Kind: EventTainted, Reason: strings.Join(e.Fired, ", "),
+					Flap: ok && now.Sub(released) <= opts.FlapWindow}
+				result.Timeline = append(result.Timeline, event)
+				result.Summary.Taints++
+				if event.Flap {
+					result.Summary.Flaps++
+				}
+				taintedSince[e.Node] = now
+			case !e.Tainted && tainted:
+				result.Timeline = append(result.Timeline, Event{Time: now, Node: e.Node, Kind: EventReleased})
+				result.Summary.Releases++
+				result.Summary.TaintedNodeMinutes += now.Sub(since).Minutes()
+				delete(taintedSince, e.Node)
+				releasedAt[e.Node] = now
+			}
+		}
+		result.Summary.MaxTaintedNodes = max(result.Summary.MaxTaintedNodes, len(taintedSince))
+	}
+
+	// Nodes still tainted count until the end of the trace.
+	for _, since := range taintedSince {
+		result.Summary.TaintedNodeMinutes += end.Sub(since).Minutes()
+	}
+	result.Summary.Nodes = len(nodes.nodes)
+	result.Summary.Evictions = len(nodes.evicted)
+	return result, nil
+}
+
+// offline returns a copy of cfg without the settings that reach outside
+// the simulated cluster.
+func offline(cfg *config.Config) *config.Config {
+	sim := *cfg
+	sim.CloudEvents.Enabled = false
+	sim.Pause.Enabled = false
+	sim.LeaderElection.Enabled = false
+	sim.DebugServer.Enabled = false
+	sim.Actuators = withoutWebhook(cfg.Actuators)
+	sim.Profiles = slices.Clone(cfg.Profiles)
+	for i := range sim.Profiles {
+		sim.Profiles[i].Actuators = withoutWebhook(sim.Profiles[i].Actuators)
+	}
+	return &sim
+}
+
+func withoutWebhook(actuators []string) []string {
+	return slices.DeleteFunc(slices.Clone(actuators), func(a string) bool { return a == config.ActuatorWebhook })
+}
@@ -0,0 +1,157 @@
+package simulate
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/config"
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+type fakeKubeClient struct {
+	kubernetes.KubeClientInterface
+	nodes []kubernetes.NodeInfo
+}
+
+func (f *fakeKubeClient) ListNodes(_ context.Context, _ string) ([]kubernetes.NodeInfo, error) {
+	return f.nodes, nil
+}
+
+type fakeSource map[string]*psi.NodePSI
+
+func (f fakeSource) Name() string { return "fake" }
+
+func (f fakeSource) FetchNodePSI(_ context.Context, nodeName string) (*psi.NodePSI, error) {
+	if sample, ok := f[nodeName]; ok {
+		return sample, nil
+	}
+	return nil, context.DeadlineExceeded
+}
+
+func TestRecordAndReadTrace(t *testing.T) {
+	client := &fakeKubeClient{nodes: []kubernetes.NodeInfo{
+		{Name: "node-1", Labels: map[string]string{"pool": "a"}},
+		{Name: "node-2"},
+	}}
+	source := fakeSource{"node-1": {CPU: psi.Pressure{Some: psi.Averages{Avg10: 61.2}}}}
+	t0 := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w := NewTraceWriter(&buf)
+	for _, at := range []time.Time{t0.Add(time.Minute), t0} {
+		if err := Record(context.Background(), client, source, "", at, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	samples, err := ReadTrace(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 4 || !samples[0].Time.Equal(t0) || !samples[3].Time.Equal(t0.Add(time.Minute)) {
+		t.Fatalf("ReadTrace() = %+v, want 4 samples sorted by time", samples)
+	}
+	s := samples[0]
+	if s.Node != "node-1" || s.Labels["pool"] != "a" || s.PSI == nil || s.PSI.CPU.Some.Avg10 != 61.2 {
+		t.Errorf("node-1 sample = %+v, want its labels and PSI", s)
+	}
+	if samples[1].Error == "" || samples[1].PSI != nil {
+		t.Errorf("node-2 sample = %+v, want the fetch error", samples[1])
+	}
+
+	_, err = ReadTrace(strings.NewReader("{\"node\":\"node-1\",\"time\":\"2026-10-18T09:00:00Z\",\"psi\":{}}\n\n{\"node\":\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("ReadTrace() error = %v, want the invalid line", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	cfg := &config.Config{
+		PollInterval:   30 * time.Second,
+		CooldownPeriod: time.Minute,
+		TaintKey:       "kube-dethrottler/high-load",
+		TaintEffect:    "NoSchedule",
+		Actuators:      []string{config.ActuatorTaint, config.ActuatorWebhook},
+		Thresholds: config.PSIThresholds{
+			CPU: config.PSIPressure{Some: config.PSIAverages{Avg10: 50}},
+		},
+	}
+
+	// node-1 is under pressure for the first minute and again from minute
+	// four; node-2 never is.
+	t0 := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	var trace []Sample
+	for i := range 13 {
+		at := t0.Add(time.Duration(i) * 30 * time.Second)
+		cpu := 10.0
+		if i <= 2 || i >= 8 {
+			cpu = 80
+		}
+		trace = append(trace,
+			Sample{Time: at, Node: "node-1", PSI: &psi.NodePSI{Timestamp: at, CPU: psi.Pressure{Some: psi.Averages{Avg10: cpu}}}},
+			Sample{Time: at, Node: "node-2", PSI: &psi.NodePSI{Timestamp: at}})
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	result, err := Run(context.Background(), cfg, trace, Options{}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Released once the cooldown passed after the last breach at 1m,
+	// tainted again at 4m.
+	want := []Event{
+		{Time: t0, Node: "node-1", Kind: EventTainted, Reason: "cpu.some.avg10"},
+		{Time: t0.Add(2 * time.Minute), Node: "node-1", Kind: EventReleased},
+		{Time: t0.Add(4 * time.Minute), Node: "node-1", Kind: EventTainted, Reason: "cpu.some.avg10", Flap: true},
+	}
+	if len(result.Timeline) != len(want) {
+		t.Fatalf("Timeline = %+v, want %+v", result.Timeline, want)
+	}
+	for i := range want {
+		if got := result.Timeline[i]; !got.Time.Equal(want[i].Time) || got.Node != want[i].Node ||
+			got.Kind != want[i].Kind || got.Reason != want[i].Reason || got.Flap != want[i].Flap {
+			t.Errorf("Timeline[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	s := result.Summary
+	if s.Polls != 13 || s.Nodes != 2 || s.Taints != 2 || s.Releases != 1 || s.Flaps != 1 || s.MaxTaintedNodes != 1 {
+		t.Errorf("Summary = %+v, want 13 polls, 2 nodes, 2 taints, 1 release and 1 flap", s)
+	}
+	if s.TaintedNodeMinutes != 4 {
+		t.Errorf("TaintedNodeMinutes = %v, want 2 before the release and 2 until the end", s.TaintedNodeMinutes)
+	}
+}
+
+func TestCluster_Observe(t *testing.T) {
+	ctx := context.Background()
+	c := newCluster()
+	c.observe(Sample{Node: "node-1", Labels: map[string]string{"pool": "a", "kube-dethrottler.io/pressure": "none"}})
+	if err := c.SetNodeLabel(ctx, "node-1", "kube-dethrottler.io/pressure", "cpu"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The recorded labels of later samples must not undo the actuator.
+	c.observe(Sample{Node: "node-1", Labels: map[string]string{"pool": "b", "kube-dethrottler.io/pressure": "none"}})
+	c.observe(Sample{Node: "node-2"})
+	nodes, err := c.ListNodes(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("ListNodes() = %+v, want 2 nodes", nodes)
+	}
+	if got := nodes[0].Labels; got["kube-dethrottler.io/pressure"] != "cpu" || got["pool"] != "a" {
+		t.Errorf("node-1 labels = %v, want the actuator label and the first recorded labels", got)
+	}
+	if err := c.SetNodeLabel(ctx, "node-2", "kube-dethrottler.io/pressure", "cpu"); err != nil {
+		t.Errorf("SetNodeLabel() on a node without recorded labels error = %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+// Package simulate records PSI samples to a trace and replays traces
+// through the controller in simulated time, to compare configurations
+// offline.
+package simulate
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/Fedosin/kube-dethrottler/internal/kubernetes"
+	"github.com/Fedosin/kube-dethrottler/internal/psi"
+)
+
+// maxLineSize bounds a trace line, which holds pod PSI when recorded with
+// pod analysis enabled.
+const maxLineSize = 16 << 20
+
+// Sample is a line of a trace: the PSI of a node at a point in time, or the
+// error returned when it was fetched.
+type Sample struct {
+	Time   time.Time         `json:"time"`
+	PSI    *psi.NodePSI      `json:"psi,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Node   string            `json:"node"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// TraceWriter writes samples as JSON lines.
+type TraceWriter struct {
+	enc *json.Encoder
+}
+
+// NewTraceWriter creates a TraceWriter writing to w.
+func NewTraceWriter(w io.Writer) *TraceWriter {
+	return &TraceWriter{enc: json.NewEncoder(w)}
+}
+
+// Write appends a sample to the trace.
+func (t *TraceWriter) Write(s Sample) error {
+	if err := t.enc.Encode(s); err != nil {
+		return fmt.Errorf("failed to write sample of node %s: %w", s.Node, err)
+	}
+	return nil
+}
+
+// Record lists the nodes matching nodeFilter and appends a sample of each
+// to the trace, taken at now. Failures to fetch PSI are recorded too, so
+// that replays see them.
+func Record(ctx context.Context, client kubernetes.KubeClientInterface, source psi.Source, nodeFilter string, now time.Time, w *TraceWriter) error {
+	nodes, err := client.ListNodes(ctx, nodeFilter)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		s := Sample{Time: now, Node: node.Name, Labels: node.Labels}
+		sample, err := source.FetchNodePSI(ctx, node.Name)
+		if err != nil {
+			s.Error = err.Error()
+		} else {
+			s.PSI = sample
+		}
+		if err := w.Write(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadTrace reads a trace and returns its samples sorted by time.
+func ReadTrace(r io.Reader) ([]Sample, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	var samples []Sample
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s Sample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if s.Node == "" || s.Time.IsZero() {
+			return nil, fmt.Errorf("line %d: node and time are required", line)
+		}
+		if s.PSI == nil && s.Error == "" {
+			return nil, fmt.Errorf("line %d: psi or error is required", line)
+		}
+		samples = append(samples, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trace: %w", err)
+	}
+	slices.SortStableFunc(samples, func(a, b Sample) int { return a.Time.Compare(b.Time) })
+	return samples, nil
+}